@@ -0,0 +1,8 @@
+# Read from an endpoint the provider does not model yet.
+data "fsd_api_request" "example" {
+  path = "/coffees"
+}
+
+output "coffee_names" {
+  value = [for coffee in jsondecode(data.fsd_api_request.example.response) : coffee.name]
+}
@@ -0,0 +1,2 @@
+# API objects can be imported by specifying the path of the object.
+terraform import fsd_api_object.example /widgets/12
@@ -0,0 +1,11 @@
+# Manage an object of an endpoint the provider does not model yet.
+resource "fsd_api_object" "example" {
+  path = "/widgets"
+  body = jsonencode({
+    name = "example"
+  })
+}
+
+output "widget_name" {
+  value = jsondecode(fsd_api_object.example.response).name
+}
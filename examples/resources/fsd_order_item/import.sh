@@ -0,0 +1,2 @@
+# Order items can be imported by specifying the order and coffee identifiers.
+terraform import fsd_order_item.example 123:3
@@ -0,0 +1,10 @@
+# A shared team order. Its items are left out, so each module can add its own
+# items with fsd_order_item without the order removing them again.
+resource "fsd_order" "team" {}
+
+# Add coffee to the team order from any module.
+resource "fsd_order_item" "example" {
+  order_id  = fsd_order.team.id
+  coffee_id = 3
+  quantity  = 2
+}
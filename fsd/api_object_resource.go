@@ -0,0 +1,390 @@
+package fsd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework-jsontypes/jsontypes"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Defaults of the fsd_api_object attributes.
+const (
+	apiObjectDefaultIDAttribute  = "id"
+	apiObjectDefaultCreateMethod = http.MethodPost
+	apiObjectDefaultUpdateMethod = http.MethodPut
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ resource.Resource                = &apiObjectResource{}
+	_ resource.ResourceWithConfigure   = &apiObjectResource{}
+	_ resource.ResourceWithImportState = &apiObjectResource{}
+)
+
+// apiObjectResourceModel maps the resource schema data.
+type apiObjectResourceModel struct {
+	ID           types.String         `tfsdk:"id"`
+	Path         types.String         `tfsdk:"path"`
+	ObjectPath   types.String         `tfsdk:"object_path"`
+	IDAttribute  types.String         `tfsdk:"id_attribute"`
+	CreateMethod types.String         `tfsdk:"create_method"`
+	UpdateMethod types.String         `tfsdk:"update_method"`
+	Body         jsontypes.Normalized `tfsdk:"body"`
+	Response     jsontypes.Normalized `tfsdk:"response"`
+}
+
+// NewAPIObjectResource is a helper function to simplify the provider implementation.
+func NewAPIObjectResource() resource.Resource {
+	return &apiObjectResource{}
+}
+
+// apiObjectResource is the resource implementation. It manages an object of
+// an fsd API endpoint the provider does not model yet.
+type apiObjectResource struct {
+	client *fsdClient
+}
+
+// Configure adds the provider configured client to the resource.
+func (r *apiObjectResource) Configure(_ context.Context, req resource.ConfigureRequest, _ *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData := req.ProviderData.(*fsdProviderData)
+	r.client = providerData.client
+}
+
+// Metadata returns the resource type name.
+func (r *apiObjectResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_api_object"
+}
+
+// Schema defines the schema for the resource.
+func (r *apiObjectResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Manages an object of an fsd API endpoint that the provider does not model yet. " +
+			"The object is created by sending body to path, then read, updated and deleted at object_path " +
+			"with GET, update_method and DELETE.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Description: "Identifier of the object, taken from the id_attribute field of the create response.",
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"path": schema.StringAttribute{
+				Description: "Path of the collection the object is created in, such as \"/widgets\".",
+				Required:    true,
+				Validators: []validator.String{
+					apiPath(),
+				},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"object_path": schema.StringAttribute{
+				Description: "Path of the object, such as \"/widgets/12\": path followed by the object id.",
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"id_attribute": schema.StringAttribute{
+				Description: "Top-level field of the create response that holds the object id. Defaults to \"id\".",
+				Optional:    true,
+				Computed:    true,
+				Default:     stringdefault.StaticString(apiObjectDefaultIDAttribute),
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"create_method": schema.StringAttribute{
+				Description: "HTTP method that creates the object at path. Defaults to \"POST\".",
+				Optional:    true,
+				Computed:    true,
+				Default:     stringdefault.StaticString(apiObjectDefaultCreateMethod),
+				Validators: []validator.String{
+					stringOneOf(http.MethodPost, http.MethodPut, http.MethodPatch),
+				},
+			},
+			"update_method": schema.StringAttribute{
+				Description: "HTTP method that updates the object at object_path. Defaults to \"PUT\".",
+				Optional:    true,
+				Computed:    true,
+				Default:     stringdefault.StaticString(apiObjectDefaultUpdateMethod),
+				Validators: []validator.String{
+					stringOneOf(http.MethodPut, http.MethodPatch, http.MethodPost),
+				},
+			},
+			"body": schema.StringAttribute{
+				Description: "JSON body sent when creating and updating the object, for example built with jsonencode.",
+				CustomType:  jsontypes.NormalizedType{},
+				Required:    true,
+			},
+			"response": schema.StringAttribute{
+				Description: "JSON response of the last read of the object. Decode it with jsondecode.",
+				CustomType:  jsontypes.NormalizedType{},
+				Computed:    true,
+			},
+		},
+	}
+}
+
+// Create sends the body to the collection path and reads the new object.
+func (r *apiObjectResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan apiObjectResourceModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	created, err := r.client.Request(ctx, plan.CreateMethod.ValueString(), plan.Path.ValueString(), []byte(plan.Body.ValueString()))
+	if err != nil {
+		addAPIErrorDiagnostics(
+			&resp.Diagnostics,
+			"Error Creating fsd API Object",
+			"Could not create object at "+plan.Path.ValueString(),
+			err,
+		)
+		return
+	}
+
+	id, err := responseObjectID(created, plan.IDAttribute.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error Creating fsd API Object",
+			"The object was created at "+plan.Path.ValueString()+", but its id could not be read from the response: "+err.Error(),
+		)
+		return
+	}
+
+	plan.ID = types.StringValue(id)
+	plan.ObjectPath = types.StringValue(objectPath(plan.Path.ValueString(), id))
+
+	// Save the object before reading it back, so Terraform keeps track of it
+	// even if the read fails.
+	plan.Response = jsontypes.NewNormalizedNull()
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	response, err := r.client.Request(ctx, http.MethodGet, plan.ObjectPath.ValueString(), nil)
+	if err != nil {
+		addAPIErrorDiagnostics(
+			&resp.Diagnostics,
+			"Error Reading fsd API Object",
+			"Could not read object at "+plan.ObjectPath.ValueString(),
+			err,
+		)
+		return
+	}
+
+	plan.Response, err = jsonResponseValue(response)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error Reading fsd API Object",
+			"Could not read object at "+plan.ObjectPath.ValueString()+": "+err.Error(),
+		)
+		return
+	}
+
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+}
+
+// Read refreshes the response. Objects that no longer exist are removed
+// from the state.
+func (r *apiObjectResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state apiObjectResourceModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	response, err := r.client.Request(ctx, http.MethodGet, state.ObjectPath.ValueString(), nil)
+	if isAPIStatus(err, http.StatusNotFound) {
+		tflog.Warn(ctx, "fsd API object no longer exists, removing it from state", map[string]interface{}{
+			"object_path": state.ObjectPath.ValueString(),
+		})
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	if err != nil {
+		addAPIErrorDiagnostics(
+			&resp.Diagnostics,
+			"Error Reading fsd API Object",
+			"Could not read object at "+state.ObjectPath.ValueString(),
+			err,
+		)
+		return
+	}
+
+	state.Response, err = jsonResponseValue(response)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error Reading fsd API Object",
+			"Could not read object at "+state.ObjectPath.ValueString()+": "+err.Error(),
+		)
+		return
+	}
+
+	diags = resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+}
+
+// Update sends the body to the object path and reads the object again.
+func (r *apiObjectResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan apiObjectResourceModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	objectPath := plan.ObjectPath.ValueString()
+
+	_, err := r.client.Request(ctx, plan.UpdateMethod.ValueString(), objectPath, []byte(plan.Body.ValueString()))
+	if err != nil {
+		addAPIErrorDiagnostics(
+			&resp.Diagnostics,
+			"Error Updating fsd API Object",
+			"Could not update object at "+objectPath,
+			err,
+		)
+		return
+	}
+
+	response, err := r.client.Request(ctx, http.MethodGet, objectPath, nil)
+	if err != nil {
+		addAPIErrorDiagnostics(
+			&resp.Diagnostics,
+			"Error Reading fsd API Object",
+			"Could not read object at "+objectPath,
+			err,
+		)
+		return
+	}
+
+	plan.Response, err = jsonResponseValue(response)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error Reading fsd API Object",
+			"Could not read object at "+objectPath+": "+err.Error(),
+		)
+		return
+	}
+
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+}
+
+// Delete deletes the object. An object that no longer exists has nothing
+// left to delete.
+func (r *apiObjectResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state apiObjectResourceModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	_, err := r.client.Request(ctx, http.MethodDelete, state.ObjectPath.ValueString(), nil)
+	if err != nil && !isAPIStatus(err, http.StatusNotFound) {
+		addAPIErrorDiagnostics(
+			&resp.Diagnostics,
+			"Error Deleting fsd API Object",
+			"Could not delete object at "+state.ObjectPath.ValueString(),
+			err,
+		)
+	}
+}
+
+// ImportState imports an object by its path, such as "/widgets/12". The
+// body is not imported, so the next apply sends the configured body.
+func (r *apiObjectResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	index := strings.LastIndex(req.ID, "/")
+	if !strings.HasPrefix(req.ID, "/") || index == len(req.ID)-1 {
+		resp.Diagnostics.AddError(
+			"Invalid fsd API Object Import ID",
+			fmt.Sprintf("Expected the path of the object, such as \"/widgets/12\", got: %q", req.ID),
+		)
+		return
+	}
+
+	collectionPath := req.ID[:index]
+	if collectionPath == "" {
+		collectionPath = "/"
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), req.ID[index+1:])...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("path"), collectionPath)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("object_path"), req.ID)...)
+
+	// Attributes that only exist in Terraform start from their defaults.
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id_attribute"), apiObjectDefaultIDAttribute)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("create_method"), apiObjectDefaultCreateMethod)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("update_method"), apiObjectDefaultUpdateMethod)...)
+}
+
+// jsonResponseValue returns a response body as a JSON attribute value, or
+// null if the response has no body.
+func jsonResponseValue(body []byte) (jsontypes.Normalized, error) {
+	if len(bytes.TrimSpace(body)) == 0 {
+		return jsontypes.NewNormalizedNull(), nil
+	}
+
+	if !json.Valid(body) {
+		return jsontypes.NewNormalizedNull(), fmt.Errorf("the response is not JSON")
+	}
+
+	return jsontypes.NewNormalizedValue(string(body)), nil
+}
+
+// objectPath returns the path of the object with the given id in the
+// collection at collectionPath. The id comes from the API response, so it is
+// escaped rather than trusted to be a single path segment.
+func objectPath(collectionPath string, id string) string {
+	return strings.TrimSuffix(collectionPath, "/") + "/" + url.PathEscape(id)
+}
+
+// responseObjectID returns the idAttribute field of a JSON object response
+// as a string.
+func responseObjectID(body []byte, idAttribute string) (string, error) {
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+
+	var object map[string]any
+	if err := decoder.Decode(&object); err != nil {
+		return "", fmt.Errorf("the response is not a JSON object: %w", err)
+	}
+
+	switch id := object[idAttribute].(type) {
+	case json.Number:
+		return id.String(), nil
+	case string:
+		if id != "" {
+			return id, nil
+		}
+	}
+
+	return "", fmt.Errorf("the response has no string or number %q field", idAttribute)
+}
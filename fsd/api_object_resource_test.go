@@ -0,0 +1,165 @@
+package fsd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+)
+
+func TestAccAPIObjectResource(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Create and Read testing
+			{
+				Config: providerConfig + `
+resource "fsd_api_object" "test" {
+  path = "/orders"
+  body = jsonencode([
+    {
+      coffee   = { id = 1 }
+      quantity = 2
+    },
+  ])
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrSet("fsd_api_object.test", "id"),
+					resource.TestMatchResourceAttr("fsd_api_object.test", "object_path", regexp.MustCompile(`^/orders/\d+$`)),
+					resource.TestCheckResourceAttr("fsd_api_object.test", "create_method", "POST"),
+					resource.TestCheckResourceAttr("fsd_api_object.test", "update_method", "PUT"),
+					resource.TestMatchResourceAttr("fsd_api_object.test", "response", regexp.MustCompile(`"quantity":\s*2`)),
+				),
+			},
+			// ImportState testing
+			{
+				ResourceName:            "fsd_api_object.test",
+				ImportState:             true,
+				ImportStateIdFunc:       testAccAttribute("fsd_api_object.test", "object_path"),
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"body"},
+			},
+			// Update and Read testing
+			{
+				Config: providerConfig + `
+resource "fsd_api_object" "test" {
+  path = "/orders"
+  body = jsonencode([
+    {
+      coffee   = { id = 1 }
+      quantity = 3
+    },
+  ])
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestMatchResourceAttr("fsd_api_object.test", "response", regexp.MustCompile(`"quantity":\s*3`)),
+				),
+			},
+			// Delete testing automatically occurs in TestCase
+		},
+	})
+}
+
+func TestClientRequest(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if r.URL.Path != "/widgets" || r.Header.Get("Content-Type") != "application/json" || string(body) != `{"name":"a"}` {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"id":12,"name":"a"}`))
+	}))
+	defer server.Close()
+
+	client := newTestClient(server.URL)
+
+	response, err := client.Request(context.Background(), http.MethodPost, "/widgets", []byte(`{"name":"a"}`))
+	if err != nil {
+		t.Fatalf("expected a 201 response to succeed, got: %s", err)
+	}
+
+	id, err := responseObjectID(response, "id")
+	if err != nil || id != "12" {
+		t.Errorf("expected id 12, got %q, %v", id, err)
+	}
+
+	if _, err := client.Request(context.Background(), http.MethodGet, "widgets", nil); err == nil {
+		t.Error("expected a path without a leading slash to be rejected")
+	}
+}
+
+func TestResponseObjectID(t *testing.T) {
+	for body, expected := range map[string]string{
+		`{"id":"abc"}`:            "abc",
+		`{"id":9007199254740993}`: "9007199254740993",
+		`{"uuid":"x","id":1.5}`:   "1.5",
+		`{"name":"no id"}`:        "",
+		`{"id":""}`:               "",
+		`{"id":{"nested":true}}`:  "",
+		`[{"id":1}]`:              "",
+		`not json`:                "",
+	} {
+		id, err := responseObjectID([]byte(body), "id")
+		if expected == "" {
+			if err == nil {
+				t.Errorf("%s: expected an error, got id %q", body, id)
+			}
+			continue
+		}
+		if err != nil || id != expected {
+			t.Errorf("%s: expected id %q, got %q, %v", body, expected, id, err)
+		}
+	}
+}
+
+func TestObjectPath(t *testing.T) {
+	for id, expected := range map[string]string{
+		"42":         "/widgets/42",
+		"a/b":        "/widgets/a%2Fb",
+		"../admin":   "/widgets/..%2Fadmin",
+		"x?y=1#frag": "/widgets/x%3Fy=1%23frag",
+		"with space": "/widgets/with%20space",
+	} {
+		if got := objectPath("/widgets/", id); got != expected {
+			t.Errorf("%q: expected %s, got %s", id, expected, got)
+		}
+	}
+}
+
+func TestJSONResponseValue(t *testing.T) {
+	if value, err := jsonResponseValue([]byte(" \n")); err != nil || !value.IsNull() {
+		t.Errorf("expected an empty response to be null, got %s, %v", value, err)
+	}
+
+	if _, err := jsonResponseValue([]byte("<html>")); err == nil {
+		t.Error("expected an error for a response that is not JSON")
+	}
+
+	value, err := jsonResponseValue([]byte(`{"a": 1}`))
+	if err != nil || value.ValueString() != `{"a": 1}` {
+		t.Errorf("expected the response as-is, got %s, %v", value, err)
+	}
+}
+
+// testAccAttribute returns an import ID function that imports a resource by
+// one of its attributes.
+func testAccAttribute(resourceName string, attribute string) resource.ImportStateIdFunc {
+	return func(s *terraform.State) (string, error) {
+		rs, ok := s.RootModule().Resources[resourceName]
+		if !ok {
+			return "", fmt.Errorf("resource not found: %s", resourceName)
+		}
+
+		return rs.Primary.Attributes[attribute], nil
+	}
+}
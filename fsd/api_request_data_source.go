@@ -0,0 +1,135 @@
+package fsd
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/hashicorp/terraform-plugin-framework-jsontypes/jsontypes"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ datasource.DataSource              = &apiRequestDataSource{}
+	_ datasource.DataSourceWithConfigure = &apiRequestDataSource{}
+)
+
+// NewAPIRequestDataSource is a helper function to simplify the provider implementation.
+func NewAPIRequestDataSource() datasource.DataSource {
+	return &apiRequestDataSource{}
+}
+
+// apiRequestDataSource is the data source implementation. It reads from an
+// fsd API endpoint the provider does not model yet.
+type apiRequestDataSource struct {
+	client *fsdClient
+}
+
+// apiRequestDataSourceModel maps the data source schema data.
+type apiRequestDataSourceModel struct {
+	ID       types.String         `tfsdk:"id"`
+	Path     types.String         `tfsdk:"path"`
+	Method   types.String         `tfsdk:"method"`
+	Body     jsontypes.Normalized `tfsdk:"body"`
+	Response jsontypes.Normalized `tfsdk:"response"`
+}
+
+// Metadata returns the data source type name.
+func (d *apiRequestDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_api_request"
+}
+
+// Schema defines the schema for the data source.
+func (d *apiRequestDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Sends a request to an fsd API endpoint that the provider does not model yet. " +
+			"The request is sent on every plan, so it must not change anything.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Description: "Path the request was sent to.",
+				Computed:    true,
+			},
+			"path": schema.StringAttribute{
+				Description: "Path of the request, such as \"/widgets/12\".",
+				Required:    true,
+				Validators: []validator.String{
+					apiPath(),
+				},
+			},
+			"method": schema.StringAttribute{
+				Description: "HTTP method of the request: \"GET\", or \"POST\" for endpoints that query with a body. Defaults to \"GET\".",
+				Optional:    true,
+				Validators: []validator.String{
+					stringOneOf(http.MethodGet, http.MethodPost),
+				},
+			},
+			"body": schema.StringAttribute{
+				Description: "JSON body of the request, for example built with jsonencode.",
+				CustomType:  jsontypes.NormalizedType{},
+				Optional:    true,
+			},
+			"response": schema.StringAttribute{
+				Description: "JSON response of the request. Decode it with jsondecode.",
+				CustomType:  jsontypes.NormalizedType{},
+				Computed:    true,
+			},
+		},
+	}
+}
+
+// Configure adds the provider configured client to the data source.
+func (d *apiRequestDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, _ *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData := req.ProviderData.(*fsdProviderData)
+	d.client = providerData.client
+}
+
+// Read sends the request and refreshes the Terraform state with the response.
+func (d *apiRequestDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var state apiRequestDataSourceModel
+	diags := req.Config.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	method := http.MethodGet
+	if !state.Method.IsNull() {
+		method = state.Method.ValueString()
+	}
+
+	var body []byte
+	if !state.Body.IsNull() {
+		body = []byte(state.Body.ValueString())
+	}
+
+	response, err := d.client.Request(ctx, method, state.Path.ValueString(), body)
+	if err != nil {
+		addAPIErrorDiagnostics(
+			&resp.Diagnostics,
+			"Unable to Send fsd API Request",
+			"Could not send "+method+" request to "+state.Path.ValueString(),
+			err,
+		)
+		return
+	}
+
+	state.Response, err = jsonResponseValue(response)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Send fsd API Request",
+			"Could not read the response of "+method+" "+state.Path.ValueString()+": "+err.Error(),
+		)
+		return
+	}
+	state.ID = state.Path
+
+	diags = resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+}
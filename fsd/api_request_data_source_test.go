@@ -0,0 +1,41 @@
+package fsd
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccAPIRequestDataSource(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: providerConfig + `
+data "fsd_api_request" "test" {
+  path = "/coffees"
+}
+
+output "coffee_count" {
+  value = length(jsondecode(data.fsd_api_request.test.response))
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.fsd_api_request.test", "id", "/coffees"),
+					resource.TestMatchResourceAttr("data.fsd_api_request.test", "response", regexp.MustCompile(`"HCP Aeropress"`)),
+					resource.TestCheckOutput("coffee_count", "9"),
+				),
+			},
+			{
+				Config: providerConfig + `
+data "fsd_api_request" "test" {
+  path   = "/coffees"
+  method = "DELETE"
+}
+`,
+				ExpectError: regexp.MustCompile(`Invalid Attribute Value`),
+			},
+		},
+	})
+}
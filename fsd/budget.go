@@ -0,0 +1,151 @@
+package fsd
+
+import (
+	"fmt"
+
+	typs "github.com/gofsd/fsd-types"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/shopspring/decimal"
+)
+
+// Values of the budget enforcement attributes.
+const (
+	budgetEnforcementStrict   = "strict"
+	budgetEnforcementAdvisory = "advisory"
+)
+
+// orderBudget holds the spend limits orders are checked against at plan
+// time. Limits that are not set are not checked.
+type orderBudget struct {
+	maxOrderTotal   decimal.NullDecimal
+	maxItemQuantity int64
+	advisory        bool
+}
+
+// orderBudgetModel maps the budget attribute of fsd_order.
+type orderBudgetModel struct {
+	MaxOrderTotal   types.Number `tfsdk:"max_order_total"`
+	MaxItemQuantity types.Int64  `tfsdk:"max_item_quantity"`
+	Enforcement     types.String `tfsdk:"enforcement"`
+}
+
+// withOverrides returns the budget with the limits set in model taking
+// precedence.
+func (b orderBudget) withOverrides(model *orderBudgetModel) (orderBudget, error) {
+	if model == nil {
+		return b, nil
+	}
+
+	if !model.MaxOrderTotal.IsNull() && !model.MaxOrderTotal.IsUnknown() {
+		limit, err := decimalFromNumber(model.MaxOrderTotal.ValueBigFloat())
+		if err != nil {
+			return b, err
+		}
+		b.maxOrderTotal = decimal.NewNullDecimal(limit)
+	}
+
+	if !model.MaxItemQuantity.IsNull() && !model.MaxItemQuantity.IsUnknown() {
+		b.maxItemQuantity = model.MaxItemQuantity.ValueInt64()
+	}
+
+	if !model.Enforcement.IsNull() && !model.Enforcement.IsUnknown() {
+		b.advisory = model.Enforcement.ValueString() == budgetEnforcementAdvisory
+	}
+
+	return b, nil
+}
+
+// needsCatalog reports whether checking the budget requires coffee prices.
+func (b orderBudget) needsCatalog() bool {
+	return b.maxOrderTotal.Valid
+}
+
+// check reports planned items that exceed the budget. Prices are taken from
+// coffees and converted to the provider currency, which the limits are in.
+// The total is not checked while any coffee or quantity is unknown.
+func (b orderBudget) check(items []orderItemModel, coffees []typs.Coffee, converter *currencyConverter) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	for i, item := range items {
+		if b.maxItemQuantity <= 0 || item.Quantity.IsUnknown() || item.Quantity.ValueInt64() <= b.maxItemQuantity {
+			continue
+		}
+
+		b.addDiagnostic(
+			&diags,
+			path.Root("items").AtListIndex(i).AtName("quantity"),
+			"Order Item Quantity Exceeds Budget",
+			fmt.Sprintf("The planned quantity %d exceeds the max_item_quantity of %d. "+
+				"Reduce the quantity or raise the budget.", item.Quantity.ValueInt64(), b.maxItemQuantity),
+		)
+	}
+
+	if !b.maxOrderTotal.Valid {
+		return diags
+	}
+
+	total, ok := orderTotal(items, coffees, converter)
+	if !ok || total.LessThanOrEqual(b.maxOrderTotal.Decimal) {
+		return diags
+	}
+
+	b.addDiagnostic(
+		&diags,
+		path.Root("items"),
+		"Order Total Exceeds Budget",
+		fmt.Sprintf("The planned order costs %s, which exceeds the max_order_total of %s. "+
+			"Reduce the items or raise the budget.",
+			formatAmount(total, converter), formatAmount(b.maxOrderTotal.Decimal, converter)),
+	)
+
+	return diags
+}
+
+// orderTotal returns the cost of items at the catalog prices of coffees,
+// converted to the provider currency. It returns false if the total cannot
+// be calculated because a coffee or quantity is unknown, or a coffee is
+// missing from the catalog and will be rejected by the API.
+func orderTotal(items []orderItemModel, coffees []typs.Coffee, converter *currencyConverter) (decimal.Decimal, bool) {
+	prices := make(map[int64]float64, len(coffees))
+	for _, coffee := range coffees {
+		prices[int64(coffee.ID)] = coffee.Price
+	}
+
+	total := decimal.Zero
+	for _, item := range items {
+		if item.Coffee.ID.IsUnknown() || item.Coffee.ID.IsNull() || item.Quantity.IsUnknown() {
+			return decimal.Zero, false
+		}
+
+		price, ok := prices[item.Coffee.ID.ValueInt64()]
+		if !ok {
+			return decimal.Zero, false
+		}
+
+		total = total.Add(converter.convertDecimal(price).Mul(decimal.NewFromInt(item.Quantity.ValueInt64())))
+	}
+
+	return total, true
+}
+
+// addDiagnostic adds a budget violation as an error, or as a warning when
+// the budget is advisory.
+func (b orderBudget) addDiagnostic(diags *diag.Diagnostics, attributePath path.Path, summary string, detail string) {
+	if b.advisory {
+		diags.AddAttributeWarning(attributePath, summary, detail+" The budget is advisory, so the plan continues.")
+		return
+	}
+
+	diags.AddAttributeError(attributePath, summary, detail)
+}
+
+// formatAmount formats an amount in the provider currency for diagnostics.
+func formatAmount(amount decimal.Decimal, converter *currencyConverter) string {
+	if currency := converter.currencyLabel(); currency != "" {
+		return amount.String() + " " + currency
+	}
+
+	return amount.String()
+}
@@ -0,0 +1,97 @@
+package fsd
+
+import (
+	"strings"
+	"testing"
+
+	typs "github.com/gofsd/fsd-types"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/shopspring/decimal"
+)
+
+func TestOrderBudgetCheck(t *testing.T) {
+	coffees := []typs.Coffee{
+		{ID: 1, Price: 200},
+		{ID: 2, Price: 3.3},
+	}
+	items := []orderItemModel{
+		{Coffee: orderItemCoffeeModel{ID: types.Int64Value(1)}, Quantity: types.Int64Value(2)},
+		{Coffee: orderItemCoffeeModel{ID: types.Int64Value(2)}, Quantity: types.Int64Value(3)},
+	}
+
+	// 2 * 200 + 3 * 3.3 = 409.9
+	within := orderBudget{maxOrderTotal: decimal.NewNullDecimal(decimal.RequireFromString("409.9"))}
+	if diags := within.check(items, coffees, nil); diags.HasError() {
+		t.Errorf("expected an order at the limit to pass, got %v", diags)
+	}
+
+	strict := orderBudget{maxOrderTotal: decimal.NewNullDecimal(decimal.RequireFromString("400"))}
+	diags := strict.check(items, coffees, nil)
+	if diags.ErrorsCount() != 1 || !strings.Contains(diags.Errors()[0].Detail(), "costs 409.9, which exceeds the max_order_total of 400") {
+		t.Errorf("expected total error, got %v", diags)
+	}
+
+	advisory := strict
+	advisory.advisory = true
+	diags = advisory.check(items, coffees, nil)
+	if diags.HasError() || diags.WarningsCount() != 1 {
+		t.Errorf("expected only a warning for an advisory budget, got %v", diags)
+	}
+
+	converter, err := newCurrencyConverter("EUR", map[string]decimal.Decimal{"EUR": decimal.RequireFromString("0.5")})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if diags := strict.check(items, coffees, converter); diags.HasError() {
+		t.Errorf("expected limit to apply in the provider currency, got %v", diags)
+	}
+}
+
+func TestOrderBudgetCheckQuantity(t *testing.T) {
+	budget, err := orderBudget{maxItemQuantity: 10}.withOverrides(&orderBudgetModel{
+		MaxOrderTotal:   types.NumberNull(),
+		MaxItemQuantity: types.Int64Value(100),
+		Enforcement:     types.StringNull(),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	items := []orderItemModel{
+		{Coffee: orderItemCoffeeModel{ID: types.Int64Value(1)}, Quantity: types.Int64Value(50)},
+		{Coffee: orderItemCoffeeModel{ID: types.Int64Value(1)}, Quantity: types.Int64Value(5000)},
+		{Coffee: orderItemCoffeeModel{ID: types.Int64Value(1)}, Quantity: types.Int64Unknown()},
+	}
+
+	diags := budget.check(items, nil, nil)
+	if diags.ErrorsCount() != 1 {
+		t.Fatalf("expected one quantity error, got %v", diags)
+	}
+
+	withPath, ok := diags.Errors()[0].(diag.DiagnosticWithPath)
+	if !ok || withPath.Path().String() != "items[1].quantity" {
+		t.Errorf("expected error at items[1].quantity, got %v", diags.Errors()[0])
+	}
+}
+
+func TestOrderTotal(t *testing.T) {
+	coffees := []typs.Coffee{{ID: 1, Price: 3.3}}
+
+	total, ok := orderTotal([]orderItemModel{
+		{Coffee: orderItemCoffeeModel{ID: types.Int64Value(1)}, Quantity: types.Int64Value(3)},
+	}, coffees, nil)
+	if !ok || total.String() != "9.9" {
+		t.Errorf("expected exact total 9.9, got %s (%t)", total, ok)
+	}
+
+	for name, item := range map[string]orderItemModel{
+		"unknown coffee":   {Coffee: orderItemCoffeeModel{ID: types.Int64Unknown()}, Quantity: types.Int64Value(1)},
+		"unknown quantity": {Coffee: orderItemCoffeeModel{ID: types.Int64Value(1)}, Quantity: types.Int64Unknown()},
+		"missing coffee":   {Coffee: orderItemCoffeeModel{ID: types.Int64Value(99)}, Quantity: types.Int64Value(1)},
+	} {
+		if _, ok := orderTotal([]orderItemModel{item}, coffees, nil); ok {
+			t.Errorf("%s: expected no total", name)
+		}
+	}
+}
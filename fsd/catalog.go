@@ -0,0 +1,102 @@
+package fsd
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	typs "github.com/gofsd/fsd-types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// catalogCacheDefaultTTL is how long the coffee catalog is reused when the
+// provider does not configure catalog_cache_ttl.
+const catalogCacheDefaultTTL = 5 * time.Minute
+
+// catalogCache shares the coffee catalog between all data sources and
+// resources of a provider instance. Concurrent lookups are deduplicated into
+// a single API request and the result is reused until the TTL expires.
+type catalogCache struct {
+	client *fsdClient
+	ttl    time.Duration
+	now    func() time.Time
+
+	mu      sync.Mutex
+	coffees []typs.Coffee
+	raw     []json.RawMessage
+	fetched time.Time
+	call    *catalogCall
+}
+
+// catalogCall is a catalog request that callers can wait on.
+type catalogCall struct {
+	done    chan struct{}
+	coffees []typs.Coffee
+	raw     []json.RawMessage
+	err     error
+}
+
+// newCatalogCache returns a catalog cache that fetches with client. A zero
+// ttl still deduplicates concurrent lookups but never reuses a result.
+func newCatalogCache(client *fsdClient, ttl time.Duration) *catalogCache {
+	return &catalogCache{
+		client: client,
+		ttl:    ttl,
+		now:    time.Now,
+	}
+}
+
+// GetCoffees returns the coffee catalog, fetching it only when the cached
+// copy has expired and no other caller is already fetching it.
+func (c *catalogCache) GetCoffees(ctx context.Context) ([]typs.Coffee, error) {
+	coffees, _, err := c.GetCoffeesRaw(ctx)
+	return coffees, err
+}
+
+// GetCoffeesRaw returns the coffee catalog like GetCoffees, together with
+// the JSON object of each coffee as sent by the API.
+func (c *catalogCache) GetCoffeesRaw(ctx context.Context) ([]typs.Coffee, []json.RawMessage, error) {
+	c.mu.Lock()
+	if c.coffees != nil && c.now().Sub(c.fetched) < c.ttl {
+		coffees, raw := c.coffees, c.raw
+		c.mu.Unlock()
+		tflog.Trace(ctx, "Using cached fsd coffee catalog")
+		return coffees, raw, nil
+	}
+
+	call := c.call
+	if call == nil {
+		call = &catalogCall{done: make(chan struct{})}
+		c.call = call
+
+		// The request outlives the caller that started it, so one
+		// cancelled operation does not fail every other waiting one.
+		go c.fetch(context.WithoutCancel(ctx), call)
+	}
+	c.mu.Unlock()
+
+	select {
+	case <-call.done:
+		return call.coffees, call.raw, call.err
+	case <-ctx.Done():
+		return nil, nil, ctx.Err()
+	}
+}
+
+func (c *catalogCache) fetch(ctx context.Context, call *catalogCall) {
+	tflog.Debug(ctx, "Fetching fsd coffee catalog")
+
+	call.coffees, call.raw, call.err = c.client.GetCoffeesRaw(ctx)
+
+	c.mu.Lock()
+	if call.err == nil {
+		c.coffees = call.coffees
+		c.raw = call.raw
+		c.fetched = c.now()
+	}
+	c.call = nil
+	c.mu.Unlock()
+
+	close(call.done)
+}
@@ -0,0 +1,86 @@
+package fsd
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestCatalogCacheDeduplicatesLookups(t *testing.T) {
+	var requests atomic.Int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		time.Sleep(20 * time.Millisecond)
+		w.Write([]byte(`[{"id":1,"name":"HCP Aeropress","price":200}]`))
+	}))
+	defer server.Close()
+
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	catalog := newCatalogCache(newTestClient(server.URL), time.Minute)
+	catalog.now = func() time.Time { return now }
+
+	// Simulate a plan with many orders and coffees data sources.
+	var wg sync.WaitGroup
+	for i := 0; i < 200; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			coffees, err := catalog.GetCoffees(context.Background())
+			if err != nil || len(coffees) != 1 {
+				t.Errorf("unexpected result: %v, %v", coffees, err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if got := requests.Load(); got != 1 {
+		t.Errorf("expected one catalog request, got %d", got)
+	}
+
+	now = now.Add(30 * time.Second)
+	if _, err := catalog.GetCoffees(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if got := requests.Load(); got != 1 {
+		t.Errorf("expected cached catalog within the TTL, got %d requests", got)
+	}
+
+	now = now.Add(time.Minute)
+	if _, err := catalog.GetCoffees(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if got := requests.Load(); got != 2 {
+		t.Errorf("expected catalog to be fetched again after the TTL, got %d requests", got)
+	}
+}
+
+func TestCatalogCacheDoesNotCacheErrors(t *testing.T) {
+	var requests atomic.Int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if requests.Add(1) == 1 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.Write([]byte(`[]`))
+	}))
+	defer server.Close()
+
+	catalog := newCatalogCache(newTestClient(server.URL), time.Minute)
+
+	if _, err := catalog.GetCoffees(context.Background()); err == nil {
+		t.Fatalf("expected first lookup to fail")
+	}
+	if _, err := catalog.GetCoffees(context.Background()); err != nil {
+		t.Fatalf("expected failed lookup to be retried, got: %s", err)
+	}
+	if got := requests.Load(); got != 2 {
+		t.Errorf("expected two catalog requests, got %d", got)
+	}
+}
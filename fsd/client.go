@@ -0,0 +1,432 @@
+package fsd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	typs "github.com/gofsd/fsd-types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+const (
+	// idempotencyKeyHeader identifies repeated order creation requests.
+	idempotencyKeyHeader = "Idempotency-Key"
+
+	// createOrderAttempts is how often an order creation whose response
+	// was lost is sent.
+	createOrderAttempts = 3
+
+	// createOrderRetryDelay is multiplied by the attempt number to space
+	// out order creation retries.
+	createOrderRetryDelay = 500 * time.Millisecond
+
+	// clientTimeout bounds a single fsd API request, like the client of
+	// typs.NewClient does.
+	clientTimeout = 10 * time.Second
+)
+
+// apiOrder is an fsd order together with the lifecycle status and the
+// timestamps the API keeps for it. Fields the API does not return are zero.
+type apiOrder struct {
+	typs.Order
+
+	Status    string    `json:"status"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	// Raw is the order as sent by the API, including fields the provider
+	// does not model.
+	Raw json.RawMessage `json:"-"`
+}
+
+// fsdClient extends the fsd-types client with the API calls the provider
+// needs that the upstream client does not expose. Every call takes a context
+// so that interrupting Terraform cancels requests that are still in flight.
+type fsdClient struct {
+	*typs.Client
+
+	// tokenCache keeps the provider token between provider processes. It
+	// is nil when caching is disabled.
+	tokenCache *tokenCache
+
+	// authMu guards Token and serializes re-authentication, so resources
+	// running in parallel sign in only once when the token expires.
+	authMu sync.RWMutex
+
+	// orderLocks serializes edits of order lines, so fsd_order_item
+	// resources of the same order do not overwrite each other.
+	orderLocks orderLocks
+}
+
+// authenticate sets the client token, reusing a cached token for the
+// configured host and username while it is valid and signing in otherwise.
+func (c *fsdClient) authenticate(ctx context.Context) error {
+	c.authMu.Lock()
+	defer c.authMu.Unlock()
+
+	if c.tokenCache != nil {
+		token, ok, err := c.tokenCache.get(c.HostURL, c.Auth.Username)
+		if err != nil {
+			tflog.Warn(ctx, "Ignoring fsd token cache", map[string]any{"error": err.Error()})
+		}
+		if ok {
+			tflog.Debug(ctx, "Using cached fsd API token")
+			c.Token = token
+			return nil
+		}
+	}
+
+	return c.signIn(ctx)
+}
+
+// reauthenticate replaces a token the API rejected. Callers that were
+// rejected with the same token wait for a single sign-in and then reuse
+// its result.
+func (c *fsdClient) reauthenticate(ctx context.Context, rejected string) error {
+	c.authMu.Lock()
+	defer c.authMu.Unlock()
+
+	if c.Token != rejected {
+		return nil
+	}
+
+	tflog.Debug(ctx, "fsd API token was rejected, signing in again")
+
+	// A rejected provider token must not be handed out again.
+	if c.tokenCache != nil {
+		if err := c.tokenCache.invalidate(c.HostURL, c.Auth.Username); err != nil {
+			tflog.Warn(ctx, "Unable to invalidate cached fsd API token", map[string]any{"error": err.Error()})
+		}
+	}
+
+	return c.signIn(ctx)
+}
+
+// signIn replaces the token with a fresh one. The caller must hold authMu.
+func (c *fsdClient) signIn(ctx context.Context) error {
+	auth, err := c.SignIn(ctx, c.Auth)
+	if err != nil {
+		return err
+	}
+	c.Token = auth.Token
+
+	if c.tokenCache != nil {
+		if err := c.tokenCache.put(c.HostURL, c.Auth.Username, c.Token); err != nil {
+			tflog.Warn(ctx, "Unable to cache fsd API token", map[string]any{"error": err.Error()})
+		}
+	}
+
+	return nil
+}
+
+// currentToken returns the provider token.
+func (c *fsdClient) currentToken() string {
+	c.authMu.RLock()
+	defer c.authMu.RUnlock()
+
+	return c.Token
+}
+
+// GetCoffees returns the coffee catalog.
+func (c *fsdClient) GetCoffees(ctx context.Context) ([]typs.Coffee, error) {
+	coffees, _, err := c.GetCoffeesRaw(ctx)
+	return coffees, err
+}
+
+// GetCoffeesRaw returns the coffee catalog together with the JSON object of
+// each coffee as sent by the API, including fields typs.Coffee does not
+// model.
+func (c *fsdClient) GetCoffeesRaw(ctx context.Context) ([]typs.Coffee, []json.RawMessage, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", fmt.Sprintf("%s/coffees", c.HostURL), nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	body, _, err := c.doAuthenticated(req)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	raw := []json.RawMessage{}
+	err = json.Unmarshal(body, &raw)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	coffees := make([]typs.Coffee, len(raw))
+	for i := range raw {
+		err = json.Unmarshal(raw[i], &coffees[i])
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return coffees, raw, nil
+}
+
+// GetOrder returns a single order and its ETag.
+func (c *fsdClient) GetOrder(ctx context.Context, orderID string) (*apiOrder, string, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", fmt.Sprintf("%s/orders/%s", c.HostURL, orderID), nil)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return c.orderRequest(req)
+}
+
+// CreateOrder creates a new order from the given items and returns it with
+// its ETag. The idempotency key lets the API recognise a repeated request,
+// so a request whose response was lost is retried and returns the order the
+// first attempt created.
+func (c *fsdClient) CreateOrder(ctx context.Context, orderItems []typs.OrderItem, idempotencyKey string) (*apiOrder, string, error) {
+	rb, err := json.Marshal(orderItems)
+	if err != nil {
+		return nil, "", err
+	}
+
+	for attempt := 1; ; attempt++ {
+		req, err := http.NewRequestWithContext(ctx, "POST", fmt.Sprintf("%s/orders", c.HostURL), strings.NewReader(string(rb)))
+		if err != nil {
+			return nil, "", err
+		}
+		req.Header.Set(idempotencyKeyHeader, idempotencyKey)
+
+		order, etag, err := c.orderRequest(req)
+		if err == nil || attempt == createOrderAttempts || ctx.Err() != nil || !isAmbiguousError(err) {
+			return order, etag, err
+		}
+
+		tflog.Debug(ctx, "Retrying fsd order creation with the same idempotency key", map[string]any{
+			"attempt": attempt,
+			"error":   err.Error(),
+		})
+
+		select {
+		case <-time.After(time.Duration(attempt) * createOrderRetryDelay):
+		case <-ctx.Done():
+			return nil, "", ctx.Err()
+		}
+	}
+}
+
+// UpdateOrder replaces the items of an existing order and returns it with
+// its new ETag. A non-empty ifMatch makes the API reject the update with
+// 412 Precondition Failed if the order changed since that ETag was read.
+func (c *fsdClient) UpdateOrder(ctx context.Context, orderID string, orderItems []typs.OrderItem, ifMatch string) (*apiOrder, string, error) {
+	rb, err := json.Marshal(orderItems)
+	if err != nil {
+		return nil, "", err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "PUT", fmt.Sprintf("%s/orders/%s", c.HostURL, orderID), strings.NewReader(string(rb)))
+	if err != nil {
+		return nil, "", err
+	}
+	setIfMatch(req, ifMatch)
+
+	return c.orderRequest(req)
+}
+
+// DeleteOrder deletes an existing order. A non-empty ifMatch makes the API
+// reject the deletion if the order changed since that ETag was read.
+func (c *fsdClient) DeleteOrder(ctx context.Context, orderID string, ifMatch string) error {
+	req, err := http.NewRequestWithContext(ctx, "DELETE", fmt.Sprintf("%s/orders/%s", c.HostURL, orderID), nil)
+	if err != nil {
+		return err
+	}
+	setIfMatch(req, ifMatch)
+
+	_, _, err = c.doAuthenticated(req)
+	return err
+}
+
+// CancelOrder cancels an existing order. Cancelled orders are kept by the
+// API for accounting. A non-empty ifMatch makes the API reject the
+// cancellation if the order changed since that ETag was read.
+func (c *fsdClient) CancelOrder(ctx context.Context, orderID string, ifMatch string) error {
+	req, err := http.NewRequestWithContext(ctx, "POST", fmt.Sprintf("%s/orders/%s/cancel", c.HostURL, orderID), nil)
+	if err != nil {
+		return err
+	}
+	setIfMatch(req, ifMatch)
+
+	_, _, err = c.doAuthenticated(req)
+	return err
+}
+
+// Request sends a request with an optional JSON body to a path of the fsd
+// API, such as "/widgets/12", and returns the response body. It reaches
+// endpoints the provider does not model yet.
+func (c *fsdClient) Request(ctx context.Context, method string, apiPath string, body []byte) ([]byte, error) {
+	if !strings.HasPrefix(apiPath, "/") {
+		return nil, fmt.Errorf("API path must start with \"/\", got: %q", apiPath)
+	}
+
+	var reader io.Reader
+	if len(body) > 0 {
+		reader = bytes.NewReader(body)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.HostURL+apiPath, reader)
+	if err != nil {
+		return nil, err
+	}
+	if reader != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	respBody, _, err := c.doAuthenticated(req)
+	return respBody, err
+}
+
+// SignUp creates a new user account and returns its id and token.
+func (c *fsdClient) SignUp(ctx context.Context, auth typs.AuthStruct) (*typs.AuthResponse, error) {
+	return c.authRequest(ctx, "signup", auth)
+}
+
+// SignIn signs in with the given credentials and returns a fresh token.
+func (c *fsdClient) SignIn(ctx context.Context, auth typs.AuthStruct) (*typs.AuthResponse, error) {
+	return c.authRequest(ctx, "signin", auth)
+}
+
+// UpdateUser changes the password of an existing user account. The request
+// is authorized with the token of the user being updated.
+func (c *fsdClient) UpdateUser(ctx context.Context, userID string, token string, auth typs.AuthStruct) error {
+	rb, err := json.Marshal(auth)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "PUT", fmt.Sprintf("%s/users/%s", c.HostURL, userID), strings.NewReader(string(rb)))
+	if err != nil {
+		return err
+	}
+
+	_, _, err = c.doRequest(req, token)
+	return err
+}
+
+// SignOut invalidates the given user token.
+func (c *fsdClient) SignOut(ctx context.Context, token string) error {
+	req, err := http.NewRequestWithContext(ctx, "POST", fmt.Sprintf("%s/signout", c.HostURL), nil)
+	if err != nil {
+		return err
+	}
+
+	_, _, err = c.doRequest(req, token)
+	return err
+}
+
+func (c *fsdClient) orderRequest(req *http.Request) (*apiOrder, string, error) {
+	body, header, err := c.doAuthenticated(req)
+	if err != nil {
+		return nil, "", err
+	}
+
+	order := apiOrder{}
+	err = json.Unmarshal(body, &order)
+	if err != nil {
+		return nil, "", err
+	}
+	order.Raw = body
+
+	return &order, header.Get("ETag"), nil
+}
+
+func (c *fsdClient) authRequest(ctx context.Context, endpoint string, auth typs.AuthStruct) (*typs.AuthResponse, error) {
+	rb, err := json.Marshal(auth)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", fmt.Sprintf("%s/%s", c.HostURL, endpoint), strings.NewReader(string(rb)))
+	if err != nil {
+		return nil, err
+	}
+
+	body, _, err := c.doRequest(req, "")
+	if err != nil {
+		return nil, err
+	}
+
+	ar := typs.AuthResponse{}
+	err = json.Unmarshal(body, &ar)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ar, nil
+}
+
+// isAmbiguousError reports whether a request failed without an API
+// response, so the API may or may not have applied it.
+func isAmbiguousError(err error) bool {
+	var apiErr *apiError
+	return err != nil && !errors.As(err, &apiErr)
+}
+
+// doAuthenticated sends req with the provider token. When the API rejects
+// the token, the client signs in again and retries the request once.
+func (c *fsdClient) doAuthenticated(req *http.Request) ([]byte, http.Header, error) {
+	token := c.currentToken()
+
+	body, header, err := c.doRequest(req, token)
+	var apiErr *apiError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusUnauthorized || c.Auth.Username == "" {
+		return body, header, err
+	}
+
+	if err := c.reauthenticate(req.Context(), token); err != nil {
+		return nil, nil, err
+	}
+
+	retry := req.Clone(req.Context())
+	if req.GetBody != nil {
+		retry.Body, err = req.GetBody()
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return c.doRequest(retry, c.currentToken())
+}
+
+// doRequest sends req and returns the body and headers of a successful
+// (2xx) response.
+func (c *fsdClient) doRequest(req *http.Request, token string) ([]byte, http.Header, error) {
+	if token != "" {
+		req.Header.Set("Authorization", token)
+	}
+
+	res, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return nil, nil, newAPIError(res.StatusCode, body)
+	}
+
+	return body, res.Header, nil
+}
+
+// setIfMatch makes req conditional on the object still having the ETag.
+func setIfMatch(req *http.Request, etag string) {
+	if etag != "" {
+		req.Header.Set("If-Match", etag)
+	}
+}
@@ -0,0 +1,332 @@
+package fsd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	typs "github.com/gofsd/fsd-types"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+)
+
+func TestClientCancelsInFlightRequest(t *testing.T) {
+	received := make(chan struct{})
+	release := make(chan struct{})
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(received)
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer server.Close()
+	defer close(release)
+
+	client := newTestClient(server.URL)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		<-received
+		cancel()
+	}()
+
+	start := time.Now()
+	_, _, err := client.GetOrder(ctx, "1")
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("expected cancellation to return promptly, took %s", elapsed)
+	}
+
+	var diags diag.Diagnostics
+	addAPIErrorDiagnostics(&diags, "Error Reading fsd Order", "Could not read fsd order ID 1", err)
+	if diags.ErrorsCount() != 1 || diags.Errors()[0].Summary() != "fsd API Request Cancelled" {
+		t.Errorf("expected cancellation diagnostic, got: %v", diags)
+	}
+}
+
+func TestClientReauthenticatesExpiredToken(t *testing.T) {
+	server := newExpiringTokenServer(3)
+	defer server.Close()
+
+	client := newTestClient(server.URL)
+	client.Auth = typs.AuthStruct{Username: "education", Password: "test123"}
+	if err := client.authenticate(context.Background()); err != nil {
+		t.Fatalf("unexpected error authenticating: %s", err)
+	}
+
+	// Tokens expire after three requests, so requests 4, 7 and 10 are
+	// rejected once and retried with a fresh token.
+	for i := 0; i < 10; i++ {
+		if _, err := client.GetCoffees(context.Background()); err != nil {
+			t.Fatalf("request %d: unexpected error: %s", i+1, err)
+		}
+	}
+
+	if got := server.signIns(); got != 4 {
+		t.Errorf("expected 4 sign-ins, got %d", got)
+	}
+}
+
+func TestClientReauthenticatesOnceForConcurrentRequests(t *testing.T) {
+	server := newExpiringTokenServer(100)
+	defer server.Close()
+
+	client := newTestClient(server.URL)
+	client.Auth = typs.AuthStruct{Username: "education", Password: "test123"}
+	client.Token = "expired-token"
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 10)
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := client.GetCoffees(context.Background())
+			errs <- err
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Errorf("unexpected error: %s", err)
+		}
+	}
+
+	if got := server.signIns(); got != 1 {
+		t.Errorf("expected a single sign-in, got %d", got)
+	}
+}
+
+func TestClientReauthenticatesOnlyOnce(t *testing.T) {
+	server := newExpiringTokenServer(0)
+	defer server.Close()
+
+	client := newTestClient(server.URL)
+	client.Auth = typs.AuthStruct{Username: "education", Password: "test123"}
+	client.Token = "expired-token"
+
+	_, err := client.GetCoffees(context.Background())
+
+	var apiErr *apiError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("expected unauthorized error, got: %v", err)
+	}
+	if got := server.signIns(); got != 1 {
+		t.Errorf("expected a single sign-in, got %d", got)
+	}
+}
+
+func TestClientCreateOrderRetriesDroppedResponse(t *testing.T) {
+	var mu sync.Mutex
+	orders := map[string]int{}
+	created := 0
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		key := r.Header.Get(idempotencyKeyHeader)
+		id, seen := orders[key]
+		if !seen {
+			created++
+			id = created
+			orders[key] = id
+		}
+		mu.Unlock()
+
+		// Accept the first attempt but drop the connection before
+		// answering, like a create that times out on the way back.
+		if !seen {
+			conn, _, err := w.(http.Hijacker).Hijack()
+			if err != nil {
+				t.Errorf("unexpected error hijacking connection: %s", err)
+				return
+			}
+			conn.Close()
+			return
+		}
+
+		fmt.Fprintf(w, `{"id":%d,"items":[{"coffee":{"id":1},"quantity":2}]}`, id)
+	}))
+	defer server.Close()
+
+	client := newTestClient(server.URL)
+	items := []typs.OrderItem{{Coffee: typs.Coffee{ID: 1}, Quantity: 2}}
+
+	order, _, err := client.CreateOrder(context.Background(), items, "key-1")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if order.ID != 1 {
+		t.Errorf("expected the order created by the dropped attempt, got ID %d", order.ID)
+	}
+
+	// Creating again with the same key, as a re-apply would, does not
+	// create a duplicate either.
+	if _, _, err := client.CreateOrder(context.Background(), items, "key-1"); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if created != 1 {
+		t.Errorf("expected a single order to be created, got %d", created)
+	}
+}
+
+func TestClientCreateOrderDoesNotRetryAPIErrors(t *testing.T) {
+	var attempts atomic.Int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts.Add(1)
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"message":"invalid order"}`))
+	}))
+	defer server.Close()
+
+	client := newTestClient(server.URL)
+	_, _, err := client.CreateOrder(context.Background(), []typs.OrderItem{{Coffee: typs.Coffee{ID: 99}, Quantity: 1}}, "key-2")
+	if isAmbiguousError(err) {
+		t.Fatalf("expected an API error, got: %v", err)
+	}
+	if got := attempts.Load(); got != 1 {
+		t.Errorf("expected a rejected order not to be retried, got %d attempts", got)
+	}
+}
+
+func TestClientOrderETags(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Header.Get("If-Match") != `"v1"` {
+			w.WriteHeader(http.StatusPreconditionFailed)
+			w.Write([]byte(`{"message":"order was modified"}`))
+			return
+		}
+
+		w.Header().Set("ETag", `"v1"`)
+		w.Write([]byte(`{"id":1,"items":[]}`))
+	}))
+	defer server.Close()
+
+	client := newTestClient(server.URL)
+
+	_, etag, err := client.GetOrder(context.Background(), "1")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if etag != `"v1"` {
+		t.Fatalf("expected ETag from response, got %q", etag)
+	}
+
+	if _, _, err := client.UpdateOrder(context.Background(), "1", nil, etag); err != nil {
+		t.Errorf("expected update with current ETag to succeed, got: %s", err)
+	}
+
+	_, _, err = client.UpdateOrder(context.Background(), "1", nil, `"v0"`)
+	var apiErr *apiError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusPreconditionFailed {
+		t.Errorf("expected precondition failure for stale ETag, got: %v", err)
+	}
+
+	if err := client.DeleteOrder(context.Background(), "1", `"v0"`); !errors.As(err, &apiErr) {
+		t.Errorf("expected delete with stale ETag to fail, got: %v", err)
+	}
+}
+
+func TestClientKeepsRawJSON(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/coffees" {
+			w.Write([]byte(`[{"id":1,"name":"Espresso","origin":"Kenya"},{"id":2,"name":"Latte"}]`))
+			return
+		}
+
+		w.Write([]byte(`{"id":1,"items":[],"courier":"bike"}`))
+	}))
+	defer server.Close()
+
+	client := newTestClient(server.URL)
+
+	coffees, raw, err := client.GetCoffeesRaw(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(coffees) != 2 || coffees[0].Name != "Espresso" || len(raw) != 2 {
+		t.Fatalf("expected 2 decoded and raw coffees, got %v and %d raw", coffees, len(raw))
+	}
+	if !strings.Contains(string(raw[0]), `"origin":"Kenya"`) {
+		t.Errorf("expected unmodeled fields in the raw coffee, got %s", raw[0])
+	}
+
+	order, _, err := client.GetOrder(context.Background(), "1")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if !strings.Contains(string(order.Raw), `"courier":"bike"`) {
+		t.Errorf("expected unmodeled fields in the raw order, got %s", order.Raw)
+	}
+}
+
+// expiringTokenServer is a local fsd API whose tokens are only accepted for
+// a fixed number of requests.
+type expiringTokenServer struct {
+	*httptest.Server
+
+	mu        sync.Mutex
+	uses      int
+	token     string
+	remaining int
+	issued    int
+}
+
+func newExpiringTokenServer(uses int) *expiringTokenServer {
+	s := &expiringTokenServer{uses: uses}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		if r.URL.Path == "/signin" {
+			s.issued++
+			s.token = fmt.Sprintf("token-%d", s.issued)
+			s.remaining = s.uses
+			fmt.Fprintf(w, `{"user_id":1,"username":"education","token":%q}`, s.token)
+			return
+		}
+
+		if r.Header.Get("Authorization") != s.token || s.remaining == 0 {
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"message":"token expired"}`))
+			return
+		}
+
+		s.remaining--
+		w.Write([]byte(`[]`))
+	}))
+
+	return s
+}
+
+func (s *expiringTokenServer) signIns() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.issued
+}
+
+// newTestClient returns a client for a local test server.
+func newTestClient(url string) *fsdClient {
+	return &fsdClient{
+		Client: &typs.Client{
+			HostURL:    url,
+			HTTPClient: &http.Client{},
+		},
+	}
+}
@@ -0,0 +1,166 @@
+package fsd
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+
+	typs "github.com/gofsd/fsd-types"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// coffeePolicy restricts which coffees of the catalog may be ordered. A
+// coffee must pass every configured list: it may not be denied by id or
+// name, and if allow lists are set it must be on them.
+type coffeePolicy struct {
+	// allowedIDs is nil when coffees are not restricted by id.
+	allowedIDs   map[int64]bool
+	deniedIDs    map[int64]bool
+	allowedNames []*regexp.Regexp
+	deniedNames  []*regexp.Regexp
+}
+
+// needsCatalog reports whether checking the policy requires coffee names.
+func (p coffeePolicy) needsCatalog() bool {
+	return len(p.allowedNames) > 0 || len(p.deniedNames) > 0
+}
+
+// check reports coffees that the policy does not allow. ids are the
+// items[*].coffee.id values of a resource, in order. Names are looked up in
+// coffees, and coffees missing from it are left for the API to reject.
+func (p coffeePolicy) check(ids []types.Int64, coffees []typs.Coffee) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	names := coffeeNames(coffees)
+	for i, id := range ids {
+		if id.IsNull() || id.IsUnknown() {
+			continue
+		}
+
+		idPath := path.Root("items").AtListIndex(i).AtName("coffee").AtName("id")
+		diags.Append(p.checkCoffee(idPath, id.ValueInt64(), names)...)
+	}
+
+	return diags
+}
+
+// checkCoffee reports a single coffee that the policy does not allow at
+// idPath. names maps coffee ids to their catalog names.
+func (p coffeePolicy) checkCoffee(idPath path.Path, coffeeID int64, names map[int64]string) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	if p.deniedIDs[coffeeID] {
+		diags.AddAttributeError(
+			idPath,
+			"Coffee Not Allowed",
+			fmt.Sprintf("Coffee %d is listed in the provider denied_coffee_ids.", coffeeID),
+		)
+		return diags
+	}
+
+	if p.allowedIDs != nil && !p.allowedIDs[coffeeID] {
+		diags.AddAttributeError(
+			idPath,
+			"Coffee Not Allowed",
+			fmt.Sprintf("Coffee %d is not listed in the provider allowed_coffee_ids.", coffeeID),
+		)
+		return diags
+	}
+
+	name, ok := names[coffeeID]
+	if !ok {
+		return diags
+	}
+
+	if pattern := firstMatch(p.deniedNames, name); pattern != nil {
+		diags.AddAttributeError(
+			idPath,
+			"Coffee Not Allowed",
+			fmt.Sprintf("Coffee %d (%s) matches the provider denied_coffee_name_patterns entry %q.", coffeeID, name, pattern),
+		)
+		return diags
+	}
+
+	if len(p.allowedNames) > 0 && firstMatch(p.allowedNames, name) == nil {
+		diags.AddAttributeError(
+			idPath,
+			"Coffee Not Allowed",
+			fmt.Sprintf("Coffee %d (%s) does not match any of the provider allowed_coffee_name_patterns.", coffeeID, name),
+		)
+	}
+
+	return diags
+}
+
+// coffeeNames maps the ids of coffees to their names.
+func coffeeNames(coffees []typs.Coffee) map[int64]string {
+	names := make(map[int64]string, len(coffees))
+	for _, coffee := range coffees {
+		names[int64(coffee.ID)] = coffee.Name
+	}
+
+	return names
+}
+
+// firstMatch returns the first pattern that matches name, or nil.
+func firstMatch(patterns []*regexp.Regexp, name string) *regexp.Regexp {
+	for _, pattern := range patterns {
+		if pattern.MatchString(name) {
+			return pattern
+		}
+	}
+
+	return nil
+}
+
+// modifyPlanCoffeePolicy checks the planned items[*].coffee.id values of a
+// resource against the provider coffee policy.
+func modifyPlanCoffeePolicy(ctx context.Context, policy coffeePolicy, catalog *catalogCache, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var items types.List
+	diags := req.Plan.GetAttribute(ctx, path.Root("items"), &items)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() || items.IsUnknown() {
+		return
+	}
+
+	ids := make([]types.Int64, len(items.Elements()))
+	for i, element := range items.Elements() {
+		ids[i] = types.Int64Unknown()
+
+		item, ok := element.(types.Object)
+		if !ok || item.IsNull() || item.IsUnknown() {
+			continue
+		}
+		coffee, ok := item.Attributes()["coffee"].(types.Object)
+		if !ok || coffee.IsNull() || coffee.IsUnknown() {
+			continue
+		}
+		if id, ok := coffee.Attributes()["id"].(types.Int64); ok {
+			ids[i] = id
+		}
+	}
+
+	var coffees []typs.Coffee
+	if policy.needsCatalog() && catalog != nil {
+		var err error
+		coffees, err = catalog.GetCoffees(ctx)
+		if err != nil {
+			addAPIErrorDiagnostics(
+				&resp.Diagnostics,
+				"Error Checking Allowed fsd Coffees",
+				"Could not read the fsd coffee catalog",
+				err,
+			)
+			return
+		}
+	}
+
+	resp.Diagnostics.Append(policy.check(ids, coffees)...)
+}
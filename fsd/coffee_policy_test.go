@@ -0,0 +1,72 @@
+package fsd
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+
+	typs "github.com/gofsd/fsd-types"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestCoffeePolicyCheck(t *testing.T) {
+	coffees := []typs.Coffee{
+		{ID: 1, Name: "HCP Aeropress"},
+		{ID: 2, Name: "Packer Spiced Latte"},
+		{ID: 3, Name: "Vagrante Decaf"},
+	}
+
+	tests := map[string]struct {
+		policy   coffeePolicy
+		ids      []types.Int64
+		expected map[string]string
+	}{
+		"denied id": {
+			policy:   coffeePolicy{deniedIDs: map[int64]bool{2: true}},
+			ids:      []types.Int64{types.Int64Value(1), types.Int64Value(2)},
+			expected: map[string]string{"items[1].coffee.id": "denied_coffee_ids"},
+		},
+		"allowed ids": {
+			policy:   coffeePolicy{allowedIDs: map[int64]bool{1: true}},
+			ids:      []types.Int64{types.Int64Value(3), types.Int64Value(1)},
+			expected: map[string]string{"items[0].coffee.id": "allowed_coffee_ids"},
+		},
+		"denied name": {
+			policy:   coffeePolicy{deniedNames: []*regexp.Regexp{regexp.MustCompile(`(?i)decaf`)}},
+			ids:      []types.Int64{types.Int64Value(3)},
+			expected: map[string]string{"items[0].coffee.id": "Vagrante Decaf"},
+		},
+		"allowed names": {
+			policy:   coffeePolicy{allowedNames: []*regexp.Regexp{regexp.MustCompile(`^HCP `)}},
+			ids:      []types.Int64{types.Int64Value(1), types.Int64Value(2)},
+			expected: map[string]string{"items[1].coffee.id": "allowed_coffee_name_patterns"},
+		},
+		"unknown and missing coffees": {
+			policy:   coffeePolicy{allowedNames: []*regexp.Regexp{regexp.MustCompile(`^HCP `)}},
+			ids:      []types.Int64{types.Int64Unknown(), types.Int64Value(99)},
+			expected: map[string]string{},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			diags := test.policy.check(test.ids, coffees)
+
+			if diags.ErrorsCount() != len(test.expected) {
+				t.Fatalf("expected %d errors, got %v", len(test.expected), diags)
+			}
+			for _, d := range diags.Errors() {
+				withPath, ok := d.(diag.DiagnosticWithPath)
+				if !ok {
+					t.Fatalf("expected an attribute error, got %v", d)
+				}
+
+				expected, ok := test.expected[withPath.Path().String()]
+				if !ok || !strings.Contains(d.Detail(), expected) {
+					t.Errorf("unexpected error at %s: %s", withPath.Path(), d.Detail())
+				}
+			}
+		})
+	}
+}
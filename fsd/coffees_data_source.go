@@ -3,7 +3,7 @@ package fsd
 import (
 	"context"
 
-	typs "github.com/gofsd/fsd-types"
+	"github.com/hashicorp/terraform-plugin-framework-jsontypes/jsontypes"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/types"
@@ -22,7 +22,8 @@ func NewCoffeesDataSource() datasource.DataSource {
 
 // coffeesDataSource is the data source implementation.
 type coffeesDataSource struct {
-	client *typs.Client
+	catalog   *catalogCache
+	converter *currencyConverter
 }
 
 // coffeesDataSourceModel maps the data source schema data.
@@ -33,13 +34,16 @@ type coffeesDataSourceModel struct {
 
 // coffeesModel maps coffees schema data.
 type coffeesModel struct {
-	ID          types.Int64               `tfsdk:"id"`
-	Name        types.String              `tfsdk:"name"`
-	Teaser      types.String              `tfsdk:"teaser"`
-	Description types.String              `tfsdk:"description"`
-	Price       types.Float64             `tfsdk:"price"`
-	Image       types.String              `tfsdk:"image"`
-	Ingredients []coffeesIngredientsModel `tfsdk:"ingredients"`
+	ID             types.Int64               `tfsdk:"id"`
+	Name           types.String              `tfsdk:"name"`
+	Teaser         types.String              `tfsdk:"teaser"`
+	Description    types.String              `tfsdk:"description"`
+	Price          moneyValue                `tfsdk:"price"`
+	PriceConverted moneyValue                `tfsdk:"price_converted"`
+	Currency       types.String              `tfsdk:"currency"`
+	Image          types.String              `tfsdk:"image"`
+	Ingredients    []coffeesIngredientsModel `tfsdk:"ingredients"`
+	RawJSON        jsontypes.Normalized      `tfsdk:"raw_json"`
 }
 
 // coffeesIngredientsModel maps coffee ingredients data
@@ -82,8 +86,18 @@ func (d *coffeesDataSource) Schema(_ context.Context, _ datasource.SchemaRequest
 							Description: "Product description of the coffee.",
 							Computed:    true,
 						},
-						"price": schema.Float64Attribute{
+						"price": schema.NumberAttribute{
 							Description: "Suggested cost of the coffee.",
+							CustomType:  moneyType{},
+							Computed:    true,
+						},
+						"price_converted": schema.NumberAttribute{
+							Description: "Suggested cost of the coffee in the provider currency. Equal to price unless the provider currency is set.",
+							CustomType:  moneyType{},
+							Computed:    true,
+						},
+						"currency": schema.StringAttribute{
+							Description: "Currency of price_converted, or null if prices are not converted.",
 							Computed:    true,
 						},
 						"image": schema.StringAttribute{
@@ -102,6 +116,12 @@ func (d *coffeesDataSource) Schema(_ context.Context, _ datasource.SchemaRequest
 								},
 							},
 						},
+						"raw_json": schema.StringAttribute{
+							Description: "The coffee as returned by the fsd API, including fields the provider does not model yet. " +
+								"Decode it with jsondecode.",
+							CustomType: jsontypes.NormalizedType{},
+							Computed:   true,
+						},
 					},
 				},
 			},
@@ -113,24 +133,29 @@ func (d *coffeesDataSource) Schema(_ context.Context, _ datasource.SchemaRequest
 func (d *coffeesDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
 	var state coffeesDataSourceModel
 
-	coffees, err := d.client.GetCoffees()
+	coffees, raw, err := d.catalog.GetCoffeesRaw(ctx)
 	if err != nil {
-		resp.Diagnostics.AddError(
+		addAPIErrorDiagnostics(
+			&resp.Diagnostics,
 			"Unable to Read fsd Coffees",
-			err.Error(),
+			"Could not read the fsd coffee catalog",
+			err,
 		)
 		return
 	}
 
 	// Map response body to model
-	for _, coffee := range coffees {
+	for i, coffee := range coffees {
 		coffeeState := coffeesModel{
-			ID:          types.Int64Value(int64(coffee.ID)),
-			Name:        types.StringValue(coffee.Name),
-			Teaser:      types.StringValue(coffee.Teaser),
-			Description: types.StringValue(coffee.Description),
-			Price:       types.Float64Value(coffee.Price),
-			Image:       types.StringValue(coffee.Image),
+			ID:             types.Int64Value(int64(coffee.ID)),
+			Name:           types.StringValue(coffee.Name),
+			Teaser:         types.StringValue(coffee.Teaser),
+			Description:    types.StringValue(coffee.Description),
+			Price:          moneyFromFloat64(coffee.Price),
+			PriceConverted: d.converter.convert(coffee.Price),
+			Currency:       d.converter.currencyValue(),
+			Image:          types.StringValue(coffee.Image),
+			RawJSON:        rawJSONValue(raw[i]),
 		}
 
 		for _, ingredient := range coffee.Ingredient {
@@ -158,5 +183,7 @@ func (d *coffeesDataSource) Configure(_ context.Context, req datasource.Configur
 		return
 	}
 
-	d.client = req.ProviderData.(*typs.Client)
+	providerData := req.ProviderData.(*fsdProviderData)
+	d.catalog = providerData.catalog
+	d.converter = providerData.converter
 }
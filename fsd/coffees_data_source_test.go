@@ -1,6 +1,7 @@
 package fsd
 
 import (
+	"regexp"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
@@ -25,6 +26,9 @@ func TestAccCoffeesDataSource(t *testing.T) {
 					resource.TestCheckResourceAttr("data.fsd_coffees.test", "coffees.0.name", "HCP Aeropress"),
 					resource.TestCheckResourceAttr("data.fsd_coffees.test", "coffees.0.price", "200"),
 					resource.TestCheckResourceAttr("data.fsd_coffees.test", "coffees.0.teaser", "Automation in a cup"),
+					resource.TestCheckResourceAttr("data.fsd_coffees.test", "coffees.0.price_converted", "200"),
+					resource.TestCheckNoResourceAttr("data.fsd_coffees.test", "coffees.0.currency"),
+					resource.TestMatchResourceAttr("data.fsd_coffees.test", "coffees.0.raw_json", regexp.MustCompile(`"name":\s*"HCP Aeropress"`)),
 					// Verify placeholder id attribute
 					resource.TestCheckResourceAttr("data.fsd_coffees.test", "id", "placeholder"),
 				),
@@ -32,3 +36,32 @@ func TestAccCoffeesDataSource(t *testing.T) {
 		},
 	})
 }
+
+func TestAccCoffeesDataSourceCurrency(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+provider "fsd" {
+  username = "education"
+  password = "test123"
+  host     = "http://localhost:19090"
+
+  currency = "EUR"
+  exchange_rates = {
+    EUR = 0.92
+  }
+}
+
+data "fsd_coffees" "test" {}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.fsd_coffees.test", "coffees.0.price", "200"),
+					resource.TestCheckResourceAttr("data.fsd_coffees.test", "coffees.0.price_converted", "184"),
+					resource.TestCheckResourceAttr("data.fsd_coffees.test", "coffees.0.currency", "EUR"),
+				),
+			},
+		},
+	})
+}
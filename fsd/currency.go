@@ -0,0 +1,121 @@
+package fsd
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/shopspring/decimal"
+)
+
+// currencyConverter presents prices of the fsd API, which are in the
+// implicit base currency of the API, in the currency configured on the
+// provider. A nil converter leaves prices in the base currency.
+type currencyConverter struct {
+	currency string
+	rate     decimal.Decimal
+}
+
+// newCurrencyConverter returns a converter to currency using rates, which
+// hold the units of each currency per unit of the base currency.
+func newCurrencyConverter(currency string, rates map[string]decimal.Decimal) (*currencyConverter, error) {
+	currency = strings.ToUpper(currency)
+
+	rate, ok := rates[currency]
+	if !ok {
+		return nil, fmt.Errorf("no exchange rate for currency %s", currency)
+	}
+	if !rate.IsPositive() {
+		return nil, fmt.Errorf("exchange rate for currency %s must be greater than zero, got %s", currency, rate)
+	}
+
+	return &currencyConverter{
+		currency: currency,
+		rate:     rate,
+	}, nil
+}
+
+// convert returns a price of the fsd API in the provider currency.
+func (c *currencyConverter) convert(price float64) moneyValue {
+	if c == nil {
+		return moneyFromFloat64(price)
+	}
+
+	amount, _, err := big.ParseFloat(c.convertDecimal(price).String(), 10, moneyPrecision, big.ToNearestEven)
+	if err != nil {
+		return moneyNull()
+	}
+
+	return moneyFromBigFloat(amount)
+}
+
+// convertDecimal returns a price of the fsd API in the provider currency as
+// a decimal, for calculations.
+func (c *currencyConverter) convertDecimal(price float64) decimal.Decimal {
+	amount := decimal.NewFromFloat(price)
+	if c == nil {
+		return amount
+	}
+
+	return amount.Mul(c.rate).Round(moneyScale)
+}
+
+// currencyLabel returns the currency code to show next to converted
+// amounts, or "" if prices are not converted.
+func (c *currencyConverter) currencyLabel() string {
+	if c == nil {
+		return ""
+	}
+
+	return c.currency
+}
+
+// currencyValue returns the currency prices are converted to, or null if
+// they are left in the base currency.
+func (c *currencyConverter) currencyValue() types.String {
+	if c == nil {
+		return types.StringNull()
+	}
+
+	return types.StringValue(c.currency)
+}
+
+// loadExchangeRatesFile reads exchange rates from a JSON object that maps
+// currency codes to rates, given as numbers or decimal strings.
+func loadExchangeRatesFile(path string) (map[string]decimal.Decimal, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("exchange rates file %s is not a JSON object: %w", path, err)
+	}
+
+	rates := make(map[string]decimal.Decimal, len(raw))
+	for currency, value := range raw {
+		// decimal.Decimal decodes both JSON numbers and strings without
+		// going through float64.
+		var rate decimal.Decimal
+		if err := json.Unmarshal(value, &rate); err != nil {
+			return nil, fmt.Errorf("invalid exchange rate for currency %s in %s: %w", currency, path, err)
+		}
+		rates[strings.ToUpper(currency)] = rate
+	}
+
+	return rates, nil
+}
+
+// decimalFromNumber converts a number of the Terraform configuration, such
+// as an exchange rate or a spend limit, to a decimal.
+func decimalFromNumber(number *big.Float) (decimal.Decimal, error) {
+	if number == nil {
+		return decimal.Decimal{}, fmt.Errorf("value must not be null")
+	}
+
+	return decimal.NewFromString(number.Text('f', -1))
+}
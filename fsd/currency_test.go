@@ -0,0 +1,63 @@
+package fsd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestCurrencyConverter(t *testing.T) {
+	converter, err := newCurrencyConverter("eur", map[string]decimal.Decimal{
+		"EUR": decimal.RequireFromString("0.92"),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if got := formatMoney(converter.convert(3.3).ValueBigFloat()); got != "3.036" {
+		t.Errorf("expected 3.3 to convert to exactly 3.036, got %s", got)
+	}
+	if got := converter.currencyValue().ValueString(); got != "EUR" {
+		t.Errorf("expected currency EUR, got %s", got)
+	}
+
+	var unconverted *currencyConverter
+	if got := formatMoney(unconverted.convert(3.3).ValueBigFloat()); got != "3.3" {
+		t.Errorf("expected prices to be kept without a currency, got %s", got)
+	}
+	if !unconverted.currencyValue().IsNull() {
+		t.Errorf("expected null currency without a currency")
+	}
+
+	if _, err := newCurrencyConverter("JPY", map[string]decimal.Decimal{}); err == nil {
+		t.Errorf("expected an error for a currency without a rate")
+	}
+}
+
+func TestLoadExchangeRatesFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "rates.json")
+	if err := os.WriteFile(path, []byte(`{"eur": 0.92, "JPY": "151.37"}`), 0o600); err != nil {
+		t.Fatalf("unexpected error writing rates file: %s", err)
+	}
+
+	rates, err := loadExchangeRatesFile(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if rate := rates["EUR"]; !rate.Equal(decimal.RequireFromString("0.92")) {
+		t.Errorf("expected EUR rate 0.92, got %s", rate)
+	}
+	if rate := rates["JPY"]; !rate.Equal(decimal.RequireFromString("151.37")) {
+		t.Errorf("expected JPY rate 151.37, got %s", rate)
+	}
+
+	if err := os.WriteFile(path, []byte(`{"EUR": "cheap"}`), 0o600); err != nil {
+		t.Fatalf("unexpected error writing rates file: %s", err)
+	}
+	if _, err := loadExchangeRatesFile(path); err == nil {
+		t.Errorf("expected an error for an invalid rate")
+	}
+}
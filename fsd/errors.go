@@ -0,0 +1,188 @@
+package fsd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+)
+
+// apiError is returned by fsdClient when the fsd API answers with an
+// unexpected status code.
+type apiError struct {
+	StatusCode int              `json:"-"`
+	Message    string           `json:"message"`
+	Details    []apiErrorDetail `json:"details"`
+}
+
+// apiErrorDetail describes a problem with a single field of the request.
+type apiErrorDetail struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// newAPIError decodes an fsd API error payload. Bodies that are not JSON
+// are used as the message as-is.
+func newAPIError(statusCode int, body []byte) *apiError {
+	apiErr := &apiError{}
+
+	var payload struct {
+		apiError
+		ErrorMessage string `json:"error"`
+	}
+	if err := json.Unmarshal(body, &payload); err == nil {
+		apiErr = &payload.apiError
+		if apiErr.Message == "" {
+			apiErr.Message = payload.ErrorMessage
+		}
+	} else {
+		apiErr.Message = strings.TrimSpace(redactBody(body))
+	}
+
+	apiErr.StatusCode = statusCode
+	if apiErr.Message == "" {
+		apiErr.Message = http.StatusText(statusCode)
+	}
+
+	return apiErr
+}
+
+// Error implements error.
+func (e *apiError) Error() string {
+	msg := fmt.Sprintf("status: %d, message: %s", e.StatusCode, e.Message)
+	for _, detail := range e.Details {
+		msg += fmt.Sprintf("\n  %s: %s", detail.Field, detail.Message)
+	}
+
+	return msg
+}
+
+// isUnauthorized reports whether the API rejected the client credentials.
+func (e *apiError) isUnauthorized() bool {
+	return e.StatusCode == http.StatusUnauthorized || e.StatusCode == http.StatusForbidden
+}
+
+// isAPIStatus reports whether err is an apiError with the given status code.
+func isAPIStatus(err error, statusCode int) bool {
+	var apiErr *apiError
+	return errors.As(err, &apiErr) && apiErr.StatusCode == statusCode
+}
+
+// addAPIErrorDiagnostics turns a client error into diagnostics. Cancelled
+// requests, conflicting changes and credential failures get dedicated
+// errors, and field-level details of an apiError are attached to the
+// matching attribute path where one can be derived.
+func addAPIErrorDiagnostics(diags *diag.Diagnostics, summary string, detail string, err error) {
+	if errors.Is(err, context.Canceled) {
+		diags.AddError(
+			"fsd API Request Cancelled",
+			detail+": the operation was interrupted before the fsd API responded. "+
+				"The request may or may not have been applied; run terraform plan to reconcile the state.",
+		)
+		return
+	}
+
+	if errors.Is(err, context.DeadlineExceeded) {
+		diags.AddError(
+			"fsd API Request Timed Out",
+			detail+": the fsd API did not respond before the deadline. "+
+				"The request may or may not have been applied; run terraform plan to reconcile the state.",
+		)
+		return
+	}
+
+	var apiErr *apiError
+	if !errors.As(err, &apiErr) {
+		diags.AddError(summary, detail+": "+err.Error())
+		return
+	}
+
+	if apiErr.StatusCode == http.StatusPreconditionFailed {
+		diags.AddError(
+			"fsd Object Modified Concurrently",
+			detail+": the object was changed outside of this Terraform run since it was last read. "+
+				"Run terraform apply -refresh-only to refresh the state, review the new plan, and apply again.",
+		)
+		return
+	}
+
+	if apiErr.isUnauthorized() {
+		diags.AddError(
+			"Invalid fsd API Credentials",
+			detail+": the fsd API rejected the provider credentials "+
+				fmt.Sprintf("(status %d: %s). ", apiErr.StatusCode, apiErr.Message)+
+				"Check the username and password in the provider configuration or the "+
+				"fsd_USERNAME and fsd_PASSWORD environment variables, and that the user may perform this operation.",
+		)
+		return
+	}
+
+	attributeErrors := 0
+	for _, fieldDetail := range apiErr.Details {
+		attributePath, ok := attributePathFromAPIField(fieldDetail.Field)
+		if !ok {
+			continue
+		}
+
+		diags.AddAttributeError(attributePath, summary, detail+": "+fieldDetail.Message)
+		attributeErrors++
+	}
+
+	if attributeErrors != len(apiErr.Details) || attributeErrors == 0 {
+		diags.AddError(summary, detail+": "+apiErr.Error())
+	}
+}
+
+// addUserAPIErrorDiagnostics is addAPIErrorDiagnostics for requests sent
+// with the credentials of a managed user rather than the provider's, such as
+// those of fsd_user. Credential failures point at the user's password.
+func addUserAPIErrorDiagnostics(diags *diag.Diagnostics, summary string, detail string, username string, err error) {
+	var apiErr *apiError
+	if errors.As(err, &apiErr) && apiErr.isUnauthorized() {
+		diags.AddError(
+			"Invalid fsd User Credentials",
+			detail+": the fsd API rejected the credentials of user "+username+" "+
+				fmt.Sprintf("(status %d: %s). ", apiErr.StatusCode, apiErr.Message)+
+				"Check that password is the current password of the account, and run terraform apply -refresh-only "+
+				"to sign in again if the token in state was revoked.",
+		)
+		return
+	}
+
+	addAPIErrorDiagnostics(diags, summary, detail, err)
+}
+
+// apiFieldStep matches one step of an API field reference such as
+// "items[2].coffee.id" or "items.2.coffee.id".
+var apiFieldStep = regexp.MustCompile(`[^.\[\]]+`)
+
+// attributePathFromAPIField converts a field reference reported by the fsd
+// API into a Terraform attribute path.
+func attributePathFromAPIField(field string) (path.Path, bool) {
+	steps := apiFieldStep.FindAllString(field, -1)
+	if len(steps) == 0 {
+		return path.Empty(), false
+	}
+
+	if _, err := strconv.Atoi(steps[0]); err == nil {
+		return path.Empty(), false
+	}
+
+	attributePath := path.Root(steps[0])
+	for _, step := range steps[1:] {
+		if index, err := strconv.Atoi(step); err == nil {
+			attributePath = attributePath.AtListIndex(index)
+			continue
+		}
+		attributePath = attributePath.AtName(step)
+	}
+
+	return attributePath, true
+}
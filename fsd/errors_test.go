@@ -0,0 +1,136 @@
+package fsd
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+)
+
+func TestNewAPIError(t *testing.T) {
+	apiErr := newAPIError(http.StatusBadRequest, []byte(`{
+  "message": "invalid order",
+  "details": [{"field": "items[2].coffee.id", "message": "coffee 42 does not exist"}]
+}`))
+
+	if apiErr.StatusCode != http.StatusBadRequest {
+		t.Errorf("expected status 400, got %d", apiErr.StatusCode)
+	}
+	if apiErr.Message != "invalid order" {
+		t.Errorf("expected message to be decoded, got %q", apiErr.Message)
+	}
+	if len(apiErr.Details) != 1 || apiErr.Details[0].Field != "items[2].coffee.id" {
+		t.Errorf("expected field details to be decoded, got %v", apiErr.Details)
+	}
+
+	if got := newAPIError(http.StatusNotFound, []byte(`{"error":"no such order"}`)).Message; got != "no such order" {
+		t.Errorf("expected error key to be used as message, got %q", got)
+	}
+
+	if got := newAPIError(http.StatusInternalServerError, []byte("boom\n")).Message; got != "boom" {
+		t.Errorf("expected plain body to be used as message, got %q", got)
+	}
+
+	if got := newAPIError(http.StatusBadGateway, nil).Message; got != http.StatusText(http.StatusBadGateway) {
+		t.Errorf("expected status text for empty body, got %q", got)
+	}
+}
+
+func TestAttributePathFromAPIField(t *testing.T) {
+	expected := path.Root("items").AtListIndex(2).AtName("coffee").AtName("id")
+
+	for _, field := range []string{"items[2].coffee.id", "items.2.coffee.id"} {
+		got, ok := attributePathFromAPIField(field)
+		if !ok {
+			t.Errorf("expected %q to convert to a path", field)
+			continue
+		}
+		if !got.Equal(expected) {
+			t.Errorf("expected %q to convert to %s, got %s", field, expected, got)
+		}
+	}
+
+	for _, field := range []string{"", "[0]", "2.coffee"} {
+		if _, ok := attributePathFromAPIField(field); ok {
+			t.Errorf("expected %q not to convert to a path", field)
+		}
+	}
+}
+
+func TestAddAPIErrorDiagnostics(t *testing.T) {
+	t.Run("field details", func(t *testing.T) {
+		var diags diag.Diagnostics
+		addAPIErrorDiagnostics(&diags, "Error creating order", "Could not create order", &apiError{
+			StatusCode: http.StatusBadRequest,
+			Message:    "invalid order",
+			Details:    []apiErrorDetail{{Field: "items[2].coffee.id", Message: "coffee 42 does not exist"}},
+		})
+
+		if diags.ErrorsCount() != 1 {
+			t.Fatalf("expected one error, got %v", diags)
+		}
+
+		withPath, ok := diags.Errors()[0].(diag.DiagnosticWithPath)
+		if !ok {
+			t.Fatalf("expected an attribute diagnostic, got %v", diags.Errors()[0])
+		}
+		if expected := path.Root("items").AtListIndex(2).AtName("coffee").AtName("id"); !withPath.Path().Equal(expected) {
+			t.Errorf("expected diagnostic at %s, got %s", expected, withPath.Path())
+		}
+	})
+
+	t.Run("credentials", func(t *testing.T) {
+		for _, status := range []int{http.StatusUnauthorized, http.StatusForbidden} {
+			var diags diag.Diagnostics
+			addAPIErrorDiagnostics(&diags, "Error creating order", "Could not create order", newAPIError(status, nil))
+
+			if diags.ErrorsCount() != 1 || diags.Errors()[0].Summary() != "Invalid fsd API Credentials" {
+				t.Errorf("expected credential error for status %d, got %v", status, diags)
+			}
+		}
+	})
+
+	t.Run("concurrent modification", func(t *testing.T) {
+		var diags diag.Diagnostics
+		addAPIErrorDiagnostics(&diags, "Error Updating fsd Order", "Could not update order", newAPIError(http.StatusPreconditionFailed, nil))
+
+		if diags.ErrorsCount() != 1 || !strings.Contains(diags.Errors()[0].Detail(), "-refresh-only") {
+			t.Errorf("expected refresh guidance, got %v", diags)
+		}
+	})
+
+	t.Run("other errors", func(t *testing.T) {
+		var diags diag.Diagnostics
+		addAPIErrorDiagnostics(&diags, "Error creating order", "Could not create order", errors.New("connection refused"))
+
+		if diags.ErrorsCount() != 1 || !strings.Contains(diags.Errors()[0].Detail(), "connection refused") {
+			t.Errorf("expected generic error, got %v", diags)
+		}
+	})
+}
+
+func TestAddUserAPIErrorDiagnostics(t *testing.T) {
+	t.Run("credentials", func(t *testing.T) {
+		var diags diag.Diagnostics
+		addUserAPIErrorDiagnostics(&diags, "Error Updating fsd User", "Could not update password of user alice", "alice", newAPIError(http.StatusForbidden, nil))
+
+		if diags.ErrorsCount() != 1 || diags.Errors()[0].Summary() != "Invalid fsd User Credentials" {
+			t.Fatalf("expected user credential error, got %v", diags)
+		}
+		if detail := diags.Errors()[0].Detail(); strings.Contains(detail, "provider configuration") {
+			t.Errorf("expected no mention of the provider credentials, got: %s", detail)
+		}
+	})
+
+	t.Run("other errors", func(t *testing.T) {
+		var diags diag.Diagnostics
+		addUserAPIErrorDiagnostics(&diags, "Error Updating fsd User", "Could not update password of user alice", "alice", newAPIError(http.StatusPreconditionFailed, nil))
+
+		if diags.ErrorsCount() != 1 || diags.Errors()[0].Summary() != "fsd Object Modified Concurrently" {
+			t.Errorf("expected errors to be reported like other API errors, got %v", diags)
+		}
+	})
+}
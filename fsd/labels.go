@@ -0,0 +1,71 @@
+package fsd
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// modifyPlanLabels sets the planned labels_all of a resource to its labels
+// merged over the provider default_labels.
+func modifyPlanLabels(ctx context.Context, defaults map[string]string, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var labels types.Map
+	diags := req.Plan.GetAttribute(ctx, path.Root("labels"), &labels)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	labelsAll, diags := mergeLabels(ctx, defaults, labels)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	diags = resp.Plan.SetAttribute(ctx, path.Root("labels_all"), labelsAll)
+	resp.Diagnostics.Append(diags...)
+}
+
+// mergeLabels returns labels merged over defaults. The result is unknown if
+// any label is unknown and null if there are no labels at all.
+func mergeLabels(ctx context.Context, defaults map[string]string, labels types.Map) (types.Map, diag.Diagnostics) {
+	if labels.IsUnknown() {
+		return types.MapUnknown(types.StringType), nil
+	}
+
+	merged := map[string]string{}
+	for key, value := range defaults {
+		merged[key] = value
+	}
+
+	for key, element := range labels.Elements() {
+		if element.IsUnknown() {
+			return types.MapUnknown(types.StringType), nil
+		}
+
+		value, ok := element.(types.String)
+		if !ok || value.IsNull() {
+			continue
+		}
+		merged[key] = value.ValueString()
+	}
+
+	if len(merged) == 0 {
+		return types.MapNull(types.StringType), nil
+	}
+
+	return types.MapValueFrom(ctx, types.StringType, merged)
+}
+
+// defaultLabelsValue returns the labels_all of a resource without labels of
+// its own, as set on import.
+func defaultLabelsValue(ctx context.Context, defaults map[string]string) (types.Map, diag.Diagnostics) {
+	return mergeLabels(ctx, defaults, types.MapNull(types.StringType))
+}
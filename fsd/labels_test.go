@@ -0,0 +1,44 @@
+package fsd
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestMergeLabels(t *testing.T) {
+	ctx := context.Background()
+	defaults := map[string]string{"team": "platform", "cost-center": "1234"}
+
+	labels := types.MapValueMust(types.StringType, map[string]attr.Value{
+		"team":    types.StringValue("payments"),
+		"service": types.StringValue("checkout"),
+	})
+
+	merged, diags := mergeLabels(ctx, defaults, labels)
+	if diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+
+	expected := types.MapValueMust(types.StringType, map[string]attr.Value{
+		"team":        types.StringValue("payments"),
+		"cost-center": types.StringValue("1234"),
+		"service":     types.StringValue("checkout"),
+	})
+	if !merged.Equal(expected) {
+		t.Errorf("expected resource labels to override defaults, got %s", merged)
+	}
+
+	unknown := types.MapValueMust(types.StringType, map[string]attr.Value{
+		"team": types.StringUnknown(),
+	})
+	if merged, _ := mergeLabels(ctx, defaults, unknown); !merged.IsUnknown() {
+		t.Errorf("expected unknown labels_all for an unknown label, got %s", merged)
+	}
+
+	if merged, _ := mergeLabels(ctx, nil, types.MapNull(types.StringType)); !merged.IsNull() {
+		t.Errorf("expected null labels_all without any labels, got %s", merged)
+	}
+}
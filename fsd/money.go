@@ -0,0 +1,168 @@
+package fsd
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+const (
+	// moneyScale is the number of decimal places money amounts are
+	// compared at. Differences beyond it, such as the rounding noise of
+	// binary floating point prices, are not changes.
+	moneyScale = 6
+
+	// moneyPrecision is the mantissa precision of money amounts, the same
+	// Terraform uses for numbers.
+	moneyPrecision = 512
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ basetypes.NumberTypable                    = moneyType{}
+	_ basetypes.NumberValuableWithSemanticEquals = moneyValue{}
+)
+
+// moneyType is a number attribute type for prices. Amounts are kept as
+// decimals rather than float64 so that prices such as 3.3 are stored as
+// written.
+type moneyType struct {
+	basetypes.NumberType
+}
+
+// Equal returns true if the given type is equivalent.
+func (t moneyType) Equal(o attr.Type) bool {
+	other, ok := o.(moneyType)
+	if !ok {
+		return false
+	}
+
+	return t.NumberType.Equal(other.NumberType)
+}
+
+// String returns a human readable string of the type name.
+func (t moneyType) String() string {
+	return "moneyType"
+}
+
+// ValueFromNumber returns a moneyValue given a basetypes.NumberValue.
+func (t moneyType) ValueFromNumber(_ context.Context, in basetypes.NumberValue) (basetypes.NumberValuable, diag.Diagnostics) {
+	return moneyValue{NumberValue: in}, nil
+}
+
+// ValueFromTerraform returns a moneyValue given a tftypes.Value.
+func (t moneyType) ValueFromTerraform(ctx context.Context, in tftypes.Value) (attr.Value, error) {
+	attrValue, err := t.NumberType.ValueFromTerraform(ctx, in)
+	if err != nil {
+		return nil, err
+	}
+
+	numberValue, ok := attrValue.(basetypes.NumberValue)
+	if !ok {
+		return nil, fmt.Errorf("unexpected value type of %T", attrValue)
+	}
+
+	numberValuable, diags := t.ValueFromNumber(ctx, numberValue)
+	if diags.HasError() {
+		return nil, fmt.Errorf("unexpected error converting NumberValue to NumberValuable: %v", diags)
+	}
+
+	return numberValuable, nil
+}
+
+// ValueType returns the value type of this type.
+func (t moneyType) ValueType(_ context.Context) attr.Value {
+	return moneyValue{}
+}
+
+// moneyValue is a money amount.
+type moneyValue struct {
+	basetypes.NumberValue
+}
+
+// moneyNull returns a null money amount.
+func moneyNull() moneyValue {
+	return moneyValue{NumberValue: basetypes.NewNumberNull()}
+}
+
+// moneyUnknown returns an unknown money amount.
+func moneyUnknown() moneyValue {
+	return moneyValue{NumberValue: basetypes.NewNumberUnknown()}
+}
+
+// moneyFromBigFloat returns a known money amount.
+func moneyFromBigFloat(amount *big.Float) moneyValue {
+	return moneyValue{NumberValue: basetypes.NewNumberValue(amount)}
+}
+
+// moneyFromFloat64 converts a price of the fsd API to a money amount. The
+// shortest decimal that identifies the float64 is used, so 3.3 becomes
+// exactly 3.3 instead of its binary approximation.
+func moneyFromFloat64(price float64) moneyValue {
+	amount, _, err := big.ParseFloat(strconv.FormatFloat(price, 'f', -1, 64), 10, moneyPrecision, big.ToNearestEven)
+	if err != nil {
+		// Only NaN and infinities fail to parse, which are not prices.
+		return moneyNull()
+	}
+
+	return moneyFromBigFloat(amount)
+}
+
+// Equal returns true if the given value is equivalent.
+func (v moneyValue) Equal(o attr.Value) bool {
+	other, ok := o.(moneyValue)
+	if !ok {
+		return false
+	}
+
+	return v.NumberValue.Equal(other.NumberValue)
+}
+
+// Type returns a moneyType.
+func (v moneyValue) Type(_ context.Context) attr.Type {
+	return moneyType{}
+}
+
+// NumberSemanticEquals returns true if both amounts are the same at
+// moneyScale decimal places.
+func (v moneyValue) NumberSemanticEquals(_ context.Context, newValuable basetypes.NumberValuable) (bool, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	newValue, ok := newValuable.(moneyValue)
+	if !ok {
+		diags.AddError(
+			"Semantic Equality Check Error",
+			"An unexpected value type was received while performing semantic equality checks. "+
+				"Please report this to the provider developers.\n\n"+
+				"Expected Value Type: "+fmt.Sprintf("%T", v)+"\n"+
+				"Got Value Type: "+fmt.Sprintf("%T", newValuable),
+		)
+		return false, diags
+	}
+
+	if v.ValueBigFloat() == nil || newValue.ValueBigFloat() == nil {
+		return false, diags
+	}
+
+	return formatMoney(v.ValueBigFloat()) == formatMoney(newValue.ValueBigFloat()), diags
+}
+
+// formatMoney formats an amount rounded to moneyScale decimal places,
+// without trailing zeros.
+func formatMoney(amount *big.Float) string {
+	text := amount.Text('f', moneyScale)
+	text = strings.TrimRight(text, "0")
+	text = strings.TrimSuffix(text, ".")
+	if text == "-0" {
+		return "0"
+	}
+
+	return text
+}
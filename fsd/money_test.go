@@ -0,0 +1,41 @@
+package fsd
+
+import (
+	"context"
+	"math/big"
+	"testing"
+)
+
+func TestMoneyFromFloat64(t *testing.T) {
+	for price, expected := range map[float64]string{
+		3.3:   "3.3",
+		200:   "200",
+		-1.25: "-1.25",
+	} {
+		if got := formatMoney(moneyFromFloat64(price).ValueBigFloat()); got != expected {
+			t.Errorf("%v: expected %s, got %s", price, expected, got)
+		}
+	}
+
+	if exact := moneyFromFloat64(3.3).ValueBigFloat().Text('f', 20); exact != "3.30000000000000000000" {
+		t.Errorf("expected 3.3 to be stored as a decimal, got %s", exact)
+	}
+}
+
+func TestMoneySemanticEquals(t *testing.T) {
+	ctx := context.Background()
+
+	noisy, _, _ := big.ParseFloat("3.3000000000000003", 10, moneyPrecision, big.ToNearestEven)
+	equal, diags := moneyFromFloat64(3.3).NumberSemanticEquals(ctx, moneyFromBigFloat(noisy))
+	if diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+	if !equal {
+		t.Errorf("expected rounding noise not to be a change")
+	}
+
+	equal, _ = moneyFromFloat64(3.3).NumberSemanticEquals(ctx, moneyFromFloat64(3.31))
+	if equal {
+		t.Errorf("expected different amounts not to be equal")
+	}
+}
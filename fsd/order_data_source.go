@@ -0,0 +1,191 @@
+package fsd
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework-jsontypes/jsontypes"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ datasource.DataSource              = &orderDataSource{}
+	_ datasource.DataSourceWithConfigure = &orderDataSource{}
+)
+
+// NewOrderDataSource is a helper function to simplify the provider implementation.
+func NewOrderDataSource() datasource.DataSource {
+	return &orderDataSource{}
+}
+
+// orderDataSource is the data source implementation.
+type orderDataSource struct {
+	client    *fsdClient
+	converter *currencyConverter
+}
+
+// orderDataSourceModel maps the data source schema data.
+type orderDataSourceModel struct {
+	ID          types.String               `tfsdk:"id"`
+	Items       []orderDataSourceItemModel `tfsdk:"items"`
+	Status      types.String               `tfsdk:"status"`
+	CreatedAt   types.String               `tfsdk:"created_at"`
+	UpdatedAt   types.String               `tfsdk:"updated_at"`
+	LastUpdated types.String               `tfsdk:"last_updated"`
+	RawJSON     jsontypes.Normalized       `tfsdk:"raw_json"`
+}
+
+// orderDataSourceItemModel maps order item data. Unlike the fsd_order
+// resource it has no price snapshots.
+type orderDataSourceItemModel struct {
+	Coffee   orderItemCoffeeModel `tfsdk:"coffee"`
+	Quantity types.Int64          `tfsdk:"quantity"`
+}
+
+// Metadata returns the data source type name.
+func (d *orderDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_order"
+}
+
+// Schema defines the schema for the data source.
+func (d *orderDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Fetches a single order.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Description: "Numeric identifier of the order.",
+				Required:    true,
+			},
+			"status": schema.StringAttribute{
+				Description: "Lifecycle status of the order, such as \"pending\" or \"fulfilled\".",
+				Computed:    true,
+			},
+			"created_at": schema.StringAttribute{
+				Description: "RFC 3339 timestamp of the order creation, as recorded by the fsd API.",
+				Computed:    true,
+			},
+			"updated_at": schema.StringAttribute{
+				Description: "RFC 3339 timestamp of the last update of the order, as recorded by the fsd API.",
+				Computed:    true,
+			},
+			"last_updated": schema.StringAttribute{
+				Description:        "Deprecated alias of updated_at.",
+				DeprecationMessage: "Use updated_at instead. The last_updated attribute will be removed in a future release.",
+				Computed:           true,
+			},
+			"raw_json": schema.StringAttribute{
+				Description: "The order as returned by the fsd API, including fields the provider does not model yet. " +
+					"Decode it with jsondecode.",
+				CustomType: jsontypes.NormalizedType{},
+				Computed:   true,
+			},
+			"items": schema.ListNestedAttribute{
+				Description: "List of items in the order.",
+				Computed:    true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"quantity": schema.Int64Attribute{
+							Description: "Count of this item in the order.",
+							Computed:    true,
+						},
+						"coffee": schema.SingleNestedAttribute{
+							Description: "Coffee item in the order.",
+							Computed:    true,
+							Attributes: map[string]schema.Attribute{
+								"id": schema.Int64Attribute{
+									Description: "Numeric identifier of the coffee.",
+									Computed:    true,
+								},
+								"name": schema.StringAttribute{
+									Description: "Product name of the coffee.",
+									Computed:    true,
+								},
+								"teaser": schema.StringAttribute{
+									Description: "Fun tagline for the coffee.",
+									Computed:    true,
+								},
+								"description": schema.StringAttribute{
+									Description: "Product description of the coffee.",
+									Computed:    true,
+								},
+								"price": schema.NumberAttribute{
+									Description: "Suggested cost of the coffee.",
+									CustomType:  moneyType{},
+									Computed:    true,
+								},
+								"price_converted": schema.NumberAttribute{
+									Description: "Suggested cost of the coffee in the provider currency. Equal to price unless the provider currency is set.",
+									CustomType:  moneyType{},
+									Computed:    true,
+								},
+								"currency": schema.StringAttribute{
+									Description: "Currency of price_converted, or null if prices are not converted.",
+									Computed:    true,
+								},
+								"image": schema.StringAttribute{
+									Description: "URI for an image of the coffee.",
+									Computed:    true,
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// Read refreshes the Terraform state with the latest data.
+func (d *orderDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var state orderDataSourceModel
+	diags := req.Config.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	order, _, err := d.client.GetOrder(ctx, state.ID.ValueString())
+	if err != nil {
+		addAPIErrorDiagnostics(
+			&resp.Diagnostics,
+			"Unable to Read fsd Order",
+			"Could not read fsd order ID "+state.ID.ValueString(),
+			err,
+		)
+		return
+	}
+
+	// Map response body to model
+	state.Items = []orderDataSourceItemModel{}
+	for _, item := range orderItemsFromAPI(order.Items, d.converter) {
+		state.Items = append(state.Items, orderDataSourceItemModel{
+			Coffee:   item.Coffee,
+			Quantity: item.Quantity,
+		})
+	}
+	state.Status = optionalStringValue(order.Status)
+	state.CreatedAt = timestampValue(order.CreatedAt)
+	state.UpdatedAt = timestampValue(order.UpdatedAt)
+	state.LastUpdated = state.UpdatedAt
+	state.RawJSON = rawJSONValue(order.Raw)
+
+	// Set state
+	diags = resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// Configure adds the provider configured client to the data source.
+func (d *orderDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, _ *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData := req.ProviderData.(*fsdProviderData)
+	d.client = providerData.client
+	d.converter = providerData.converter
+}
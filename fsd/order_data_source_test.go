@@ -0,0 +1,52 @@
+package fsd
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccOrderDataSource(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Read testing
+			{
+				Config: providerConfig + `
+resource "fsd_order" "test" {
+  items = [
+    {
+      coffee = {
+        id = 1
+      }
+      quantity = 2
+    },
+  ]
+}
+
+data "fsd_order" "test" {
+  id = fsd_order.test.id
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					// Verify the order is the one managed by the resource
+					resource.TestCheckResourceAttrPair("data.fsd_order.test", "id", "fsd_order.test", "id"),
+					// Verify number of items
+					resource.TestCheckResourceAttr("data.fsd_order.test", "items.#", "1"),
+					// Verify first order item to ensure all attributes are set
+					resource.TestCheckResourceAttr("data.fsd_order.test", "items.0.quantity", "2"),
+					resource.TestCheckResourceAttr("data.fsd_order.test", "items.0.coffee.id", "1"),
+					resource.TestCheckResourceAttr("data.fsd_order.test", "items.0.coffee.description", ""),
+					resource.TestCheckResourceAttr("data.fsd_order.test", "items.0.coffee.image", "/hashicorp.png"),
+					resource.TestCheckResourceAttr("data.fsd_order.test", "items.0.coffee.name", "HCP Aeropress"),
+					resource.TestCheckResourceAttr("data.fsd_order.test", "items.0.coffee.price", "200"),
+					resource.TestCheckResourceAttr("data.fsd_order.test", "items.0.coffee.teaser", "Automation in a cup"),
+					// Verify dynamic values have any value set in the state.
+					resource.TestCheckResourceAttrSet("data.fsd_order.test", "created_at"),
+					resource.TestCheckResourceAttrSet("data.fsd_order.test", "updated_at"),
+					resource.TestCheckResourceAttrPair("data.fsd_order.test", "last_updated", "data.fsd_order.test", "updated_at"),
+				),
+			},
+		},
+	})
+}
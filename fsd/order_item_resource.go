@@ -0,0 +1,426 @@
+package fsd
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	typs "github.com/gofsd/fsd-types"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ resource.Resource                = &orderItemResource{}
+	_ resource.ResourceWithConfigure   = &orderItemResource{}
+	_ resource.ResourceWithImportState = &orderItemResource{}
+	_ resource.ResourceWithModifyPlan  = &orderItemResource{}
+)
+
+// orderItemResourceModel maps the resource schema data.
+type orderItemResourceModel struct {
+	ID       types.String `tfsdk:"id"`
+	OrderID  types.String `tfsdk:"order_id"`
+	CoffeeID types.Int64  `tfsdk:"coffee_id"`
+	Quantity types.Int64  `tfsdk:"quantity"`
+}
+
+// NewOrderItemResource is a helper function to simplify the provider implementation.
+func NewOrderItemResource() resource.Resource {
+	return &orderItemResource{}
+}
+
+// orderItemResource is the resource implementation. It manages a single
+// line of an order, so several modules can contribute to the same order.
+type orderItemResource struct {
+	client       *fsdClient
+	catalog      *catalogCache
+	budget       orderBudget
+	coffeePolicy coffeePolicy
+	converter    *currencyConverter
+}
+
+// Configure adds the provider configured client to the resource.
+func (r *orderItemResource) Configure(_ context.Context, req resource.ConfigureRequest, _ *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData := req.ProviderData.(*fsdProviderData)
+	r.client = providerData.client
+	r.catalog = providerData.catalog
+	r.budget = providerData.budget
+	r.coffeePolicy = providerData.coffeePolicy
+	r.converter = providerData.converter
+}
+
+// Metadata returns the resource type name.
+func (r *orderItemResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_order_item"
+}
+
+// Schema defines the schema for the resource.
+func (r *orderItemResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Manages a single item of an order, leaving the other items of the order untouched. " +
+			"Omit items from the fsd_order resource of an order whose items are managed by fsd_order_item, " +
+			"otherwise each apply of the order removes the items it does not list.\n\n" +
+			"The provider max_item_quantity and max_order_total apply, but the budget attribute of fsd_order does not. " +
+			"The order total is checked against the items the order holds at plan time, so it is not checked " +
+			"for an order created in the same apply, and items added to the same order in one apply are each " +
+			"checked without the others.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Description: "Identifier of the order item, in the form \"<order_id>:<coffee_id>\".",
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"order_id": schema.StringAttribute{
+				Description: "Numeric identifier of the order the item belongs to.",
+				Required:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"coffee_id": schema.Int64Attribute{
+				Description: "Numeric identifier of the coffee. An order holds at most one item per coffee.",
+				Required:    true,
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.RequiresReplace(),
+				},
+			},
+			"quantity": schema.Int64Attribute{
+				Description: "Count of the coffee in the order.",
+				Required:    true,
+				Validators: []validator.Int64{
+					int64AtLeast(1),
+				},
+			},
+		},
+	}
+}
+
+// ModifyPlan checks the planned coffee against the allowed coffees and the
+// quantity against the provider max_item_quantity and max_order_total.
+func (r *orderItemResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var plan orderItemResourceModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !plan.CoffeeID.IsUnknown() {
+		var coffees []typs.Coffee
+		if r.coffeePolicy.needsCatalog() && r.catalog != nil {
+			var err error
+			coffees, err = r.catalog.GetCoffees(ctx)
+			if err != nil {
+				addAPIErrorDiagnostics(
+					&resp.Diagnostics,
+					"Error Checking Allowed fsd Coffees",
+					"Could not read the fsd coffee catalog",
+					err,
+				)
+				return
+			}
+		}
+
+		resp.Diagnostics.Append(r.coffeePolicy.checkCoffee(path.Root("coffee_id"), plan.CoffeeID.ValueInt64(), coffeeNames(coffees))...)
+	}
+
+	if r.budget.maxItemQuantity > 0 && !plan.Quantity.IsUnknown() && plan.Quantity.ValueInt64() > r.budget.maxItemQuantity {
+		r.budget.addDiagnostic(
+			&resp.Diagnostics,
+			path.Root("quantity"),
+			"Order Item Quantity Exceeds Budget",
+			fmt.Sprintf("The planned quantity %d exceeds the max_item_quantity of %d. "+
+				"Reduce the quantity or raise the budget.", plan.Quantity.ValueInt64(), r.budget.maxItemQuantity),
+		)
+	}
+
+	r.checkOrderTotal(ctx, plan, resp)
+}
+
+// checkOrderTotal reports a planned item that takes the total of its order
+// past the provider max_order_total. The total is made of the other items
+// the order holds now and the planned item.
+func (r *orderItemResource) checkOrderTotal(ctx context.Context, plan orderItemResourceModel, resp *resource.ModifyPlanResponse) {
+	if !r.budget.needsCatalog() || r.catalog == nil ||
+		plan.OrderID.IsUnknown() || plan.CoffeeID.IsUnknown() || plan.Quantity.IsUnknown() {
+		return
+	}
+
+	order, _, err := r.client.GetOrder(ctx, plan.OrderID.ValueString())
+	if isAPIStatus(err, http.StatusNotFound) {
+		// Creating the item reports the missing order.
+		return
+	}
+	if err != nil {
+		addAPIErrorDiagnostics(
+			&resp.Diagnostics,
+			"Error Checking fsd Order Budget",
+			"Could not read fsd order ID "+plan.OrderID.ValueString(),
+			err,
+		)
+		return
+	}
+
+	coffees, err := r.catalog.GetCoffees(ctx)
+	if err != nil {
+		addAPIErrorDiagnostics(
+			&resp.Diagnostics,
+			"Error Checking fsd Order Budget",
+			"Could not read the fsd coffee catalog",
+			err,
+		)
+		return
+	}
+
+	items := orderItemsWithLine(order.Items, plan.CoffeeID.ValueInt64(), plan.Quantity.ValueInt64())
+	total, ok := orderTotal(items, coffees, r.converter)
+	if !ok || total.LessThanOrEqual(r.budget.maxOrderTotal.Decimal) {
+		return
+	}
+
+	r.budget.addDiagnostic(
+		&resp.Diagnostics,
+		path.Root("quantity"),
+		"Order Total Exceeds Budget",
+		fmt.Sprintf("With this item, order %s costs %s, which exceeds the max_order_total of %s. "+
+			"Reduce the quantity or raise the budget.",
+			plan.OrderID.ValueString(), formatAmount(total, r.converter), formatAmount(r.budget.maxOrderTotal.Decimal, r.converter)),
+	)
+}
+
+// orderItemsWithLine returns the items of an order with the line of coffeeID
+// set to quantity, for checking the budget.
+func orderItemsWithLine(orderItems []typs.OrderItem, coffeeID int64, quantity int64) []orderItemModel {
+	items := []orderItemModel{{
+		Coffee:   orderItemCoffeeModel{ID: types.Int64Value(coffeeID)},
+		Quantity: types.Int64Value(quantity),
+	}}
+
+	for _, item := range orderItems {
+		if int64(item.Coffee.ID) == coffeeID {
+			continue
+		}
+
+		items = append(items, orderItemModel{
+			Coffee:   orderItemCoffeeModel{ID: types.Int64Value(int64(item.Coffee.ID))},
+			Quantity: types.Int64Value(int64(item.Quantity)),
+		})
+	}
+
+	return items
+}
+
+// Create adds the item to the order.
+func (r *orderItemResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan orderItemResourceModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	orderID := plan.OrderID.ValueString()
+	coffeeID := int(plan.CoffeeID.ValueInt64())
+
+	_, err := r.client.EditOrderLine(ctx, orderID, coffeeID, func(line *typs.OrderItem) (*typs.OrderItem, error) {
+		if line != nil {
+			return nil, fmt.Errorf("order %s already has an item for coffee %d. "+
+				"Import it with terraform import using the id %q, or manage it in one place only", orderID, coffeeID, orderItemID(orderID, coffeeID))
+		}
+
+		next := orderLine(coffeeID, int(plan.Quantity.ValueInt64()))
+		return &next, nil
+	})
+	if err != nil {
+		addAPIErrorDiagnostics(
+			&resp.Diagnostics,
+			"Error Creating fsd Order Item",
+			"Could not add coffee "+strconv.Itoa(coffeeID)+" to order ID "+orderID,
+			err,
+		)
+		return
+	}
+
+	plan.ID = types.StringValue(orderItemID(orderID, coffeeID))
+
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+}
+
+// Read refreshes the quantity of the item. Items whose order or line no
+// longer exists are removed from the state.
+func (r *orderItemResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state orderItemResourceModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	orderID := state.OrderID.ValueString()
+	coffeeID := int(state.CoffeeID.ValueInt64())
+
+	order, _, err := r.client.GetOrder(ctx, orderID)
+	if isAPIStatus(err, http.StatusNotFound) {
+		tflog.Warn(ctx, "fsd order of the order item no longer exists, removing it from state", map[string]interface{}{
+			"order_id": orderID,
+		})
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	if err != nil {
+		addAPIErrorDiagnostics(
+			&resp.Diagnostics,
+			"Error Reading fsd Order Item",
+			"Could not read fsd order ID "+orderID,
+			err,
+		)
+		return
+	}
+
+	line := findOrderLine(order.Items, coffeeID)
+	if line == nil {
+		tflog.Warn(ctx, "fsd order item no longer exists, removing it from state", map[string]interface{}{
+			"order_id":  orderID,
+			"coffee_id": coffeeID,
+		})
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	state.ID = types.StringValue(orderItemID(orderID, coffeeID))
+	state.Quantity = types.Int64Value(int64(line.Quantity))
+
+	diags = resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+}
+
+// Update changes the quantity of the item.
+func (r *orderItemResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan orderItemResourceModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	orderID := plan.OrderID.ValueString()
+	coffeeID := int(plan.CoffeeID.ValueInt64())
+
+	_, err := r.client.EditOrderLine(ctx, orderID, coffeeID, func(line *typs.OrderItem) (*typs.OrderItem, error) {
+		if line == nil {
+			return nil, fmt.Errorf("order %s no longer has an item for coffee %d. "+
+				"Run terraform apply -refresh-only, then apply again to add it back", orderID, coffeeID)
+		}
+
+		next := orderLine(coffeeID, int(plan.Quantity.ValueInt64()))
+		return &next, nil
+	})
+	if err != nil {
+		addAPIErrorDiagnostics(
+			&resp.Diagnostics,
+			"Error Updating fsd Order Item",
+			"Could not update coffee "+strconv.Itoa(coffeeID)+" of order ID "+orderID,
+			err,
+		)
+		return
+	}
+
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+}
+
+// Delete removes the item from the order. An order that no longer exists
+// has nothing left to remove.
+func (r *orderItemResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state orderItemResourceModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	orderID := state.OrderID.ValueString()
+	coffeeID := int(state.CoffeeID.ValueInt64())
+
+	_, err := r.client.EditOrderLine(ctx, orderID, coffeeID, func(_ *typs.OrderItem) (*typs.OrderItem, error) {
+		return nil, nil
+	})
+	if err != nil && !isAPIStatus(err, http.StatusNotFound) {
+		addAPIErrorDiagnostics(
+			&resp.Diagnostics,
+			"Error Deleting fsd Order Item",
+			"Could not remove coffee "+strconv.Itoa(coffeeID)+" from order ID "+orderID,
+			err,
+		)
+	}
+}
+
+// ImportState imports an item by an id of the form "<order_id>:<coffee_id>".
+func (r *orderItemResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	orderID, coffeeID, err := parseOrderItemID(req.ID)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Invalid fsd Order Item Import ID",
+			err.Error(),
+		)
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), req.ID)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("order_id"), orderID)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("coffee_id"), coffeeID)...)
+}
+
+// orderItemID returns the id of an fsd_order_item.
+func orderItemID(orderID string, coffeeID int) string {
+	return orderID + ":" + strconv.Itoa(coffeeID)
+}
+
+// parseOrderItemID splits the id of an fsd_order_item into the order id and
+// coffee id.
+func parseOrderItemID(id string) (string, int64, error) {
+	orderID, coffee, ok := strings.Cut(id, ":")
+	if !ok || orderID == "" {
+		return "", 0, fmt.Errorf("expected an id of the form <order_id>:<coffee_id>, got: %q", id)
+	}
+
+	coffeeID, err := strconv.ParseInt(coffee, 10, 64)
+	if err != nil {
+		return "", 0, fmt.Errorf("expected a numeric coffee id in %q: %w", id, err)
+	}
+
+	return orderID, coffeeID, nil
+}
+
+// findOrderLine returns the line of items for coffeeID, or nil.
+func findOrderLine(items []typs.OrderItem, coffeeID int) *typs.OrderItem {
+	for i := range items {
+		if items[i].Coffee.ID == coffeeID {
+			return &items[i]
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,91 @@
+package fsd
+
+import (
+	"testing"
+
+	typs "github.com/gofsd/fsd-types"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccOrderItemResource(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Create and Read testing
+			{
+				Config: providerConfig + `
+resource "fsd_order" "team" {}
+
+resource "fsd_order_item" "espresso" {
+  order_id  = fsd_order.team.id
+  coffee_id = 1
+  quantity  = 2
+}
+
+resource "fsd_order_item" "latte" {
+  order_id  = fsd_order.team.id
+  coffee_id = 2
+  quantity  = 1
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckNoResourceAttr("fsd_order.team", "items.#"),
+					resource.TestCheckResourceAttr("fsd_order_item.espresso", "quantity", "2"),
+					resource.TestCheckResourceAttrPair("fsd_order_item.espresso", "order_id", "fsd_order.team", "id"),
+					resource.TestCheckResourceAttrSet("fsd_order_item.espresso", "id"),
+					resource.TestCheckResourceAttr("fsd_order_item.latte", "quantity", "1"),
+				),
+			},
+			// ImportState testing
+			{
+				ResourceName:      "fsd_order_item.espresso",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			// Update and Read testing
+			{
+				Config: providerConfig + `
+resource "fsd_order" "team" {}
+
+resource "fsd_order_item" "espresso" {
+  order_id  = fsd_order.team.id
+  coffee_id = 1
+  quantity  = 3
+}
+
+resource "fsd_order_item" "latte" {
+  order_id  = fsd_order.team.id
+  coffee_id = 2
+  quantity  = 1
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("fsd_order_item.espresso", "quantity", "3"),
+					resource.TestCheckResourceAttr("fsd_order_item.latte", "quantity", "1"),
+				),
+			},
+			// Delete testing automatically occurs in TestCase. Destroying
+			// the items changes the ETag of the order before it is deleted.
+		},
+	})
+}
+
+func TestOrderItemsWithLine(t *testing.T) {
+	coffees := []typs.Coffee{{ID: 1, Price: 200}, {ID: 2, Price: 150}}
+	orderItems := []typs.OrderItem{
+		{Coffee: typs.Coffee{ID: 1}, Quantity: 2},
+		{Coffee: typs.Coffee{ID: 2}, Quantity: 1},
+	}
+
+	// The planned quantity replaces the current line of the coffee.
+	total, ok := orderTotal(orderItemsWithLine(orderItems, 1, 3), coffees, nil)
+	if !ok || total.String() != "750" {
+		t.Errorf("expected a total of 750, got %s, %t", total, ok)
+	}
+
+	// A new line is added to the current ones.
+	total, ok = orderTotal(orderItemsWithLine(orderItems[:1], 2, 2), coffees, nil)
+	if !ok || total.String() != "700" {
+		t.Errorf("expected a total of 700, got %s, %t", total, ok)
+	}
+}
@@ -0,0 +1,136 @@
+package fsd
+
+import (
+	"context"
+	"net/http"
+	"sync"
+
+	typs "github.com/gofsd/fsd-types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// editOrderLineAttempts is how often an order line edit is tried when the
+// order keeps changing between reading and updating it.
+const editOrderLineAttempts = 3
+
+// orderLocks holds a mutex per order id. The zero value is ready to use.
+type orderLocks struct {
+	mu    sync.Mutex
+	locks map[string]*sync.Mutex
+}
+
+// lock locks the order and returns the function that unlocks it.
+func (l *orderLocks) lock(orderID string) func() {
+	l.mu.Lock()
+	if l.locks == nil {
+		l.locks = make(map[string]*sync.Mutex)
+	}
+	orderLock, ok := l.locks[orderID]
+	if !ok {
+		orderLock = &sync.Mutex{}
+		l.locks[orderID] = orderLock
+	}
+	l.mu.Unlock()
+
+	orderLock.Lock()
+	return orderLock.Unlock
+}
+
+// EditOrderLine changes the line of an order for a single coffee and leaves
+// the other lines as they are. The API only replaces all items of an order,
+// so the order is read, edited and written back with If-Match. If another
+// client changed the order in between, the edit is applied again to a fresh
+// read.
+//
+// edit receives the current line, or nil if the order has none for the
+// coffee, and returns the new line, or nil to remove it. The order is
+// returned as read after the edit.
+func (c *fsdClient) EditOrderLine(ctx context.Context, orderID string, coffeeID int, edit func(line *typs.OrderItem) (*typs.OrderItem, error)) (*apiOrder, error) {
+	unlock := c.orderLocks.lock(orderID)
+	defer unlock()
+
+	for attempt := 1; ; attempt++ {
+		order, etag, err := c.GetOrder(ctx, orderID)
+		if err != nil {
+			return nil, err
+		}
+
+		items, changed, err := editOrderLine(order.Items, coffeeID, edit)
+		if err != nil || !changed {
+			return order, err
+		}
+
+		_, _, err = c.UpdateOrder(ctx, orderID, items, etag)
+		if err == nil {
+			// UpdateOrder items are not populated, so the order is read
+			// again.
+			order, _, err = c.GetOrder(ctx, orderID)
+			return order, err
+		}
+		if attempt == editOrderLineAttempts || !isAPIStatus(err, http.StatusPreconditionFailed) {
+			return nil, err
+		}
+
+		tflog.Debug(ctx, "Retrying fsd order line edit on a changed order", map[string]any{
+			"order_id":  orderID,
+			"coffee_id": coffeeID,
+			"attempt":   attempt,
+		})
+	}
+}
+
+// editOrderLine applies edit to the line of coffeeID in items and returns
+// the items to send to the API, and whether they changed.
+func editOrderLine(items []typs.OrderItem, coffeeID int, edit func(line *typs.OrderItem) (*typs.OrderItem, error)) ([]typs.OrderItem, bool, error) {
+	index := -1
+	for i, item := range items {
+		if item.Coffee.ID == coffeeID {
+			index = i
+			break
+		}
+	}
+
+	var current *typs.OrderItem
+	if index >= 0 {
+		line := items[index]
+		current = &line
+	}
+
+	next, err := edit(current)
+	if err != nil {
+		return nil, false, err
+	}
+
+	edited := make([]typs.OrderItem, 0, len(items)+1)
+	for i, item := range items {
+		if i == index {
+			if next != nil {
+				edited = append(edited, orderLine(coffeeID, next.Quantity))
+			}
+			continue
+		}
+		edited = append(edited, orderLine(item.Coffee.ID, item.Quantity))
+	}
+
+	switch {
+	case index < 0 && next == nil:
+		return items, false, nil
+	case index < 0:
+		edited = append(edited, orderLine(coffeeID, next.Quantity))
+	case next != nil && next.Quantity == current.Quantity:
+		return items, false, nil
+	}
+
+	return edited, true, nil
+}
+
+// orderLine returns an order item as sent to the API, which only needs the
+// coffee id and quantity.
+func orderLine(coffeeID int, quantity int) typs.OrderItem {
+	return typs.OrderItem{
+		Coffee: typs.Coffee{
+			ID: coffeeID,
+		},
+		Quantity: quantity,
+	}
+}
@@ -0,0 +1,127 @@
+package fsd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	typs "github.com/gofsd/fsd-types"
+)
+
+func TestEditOrderLine(t *testing.T) {
+	items := []typs.OrderItem{orderLine(1, 2), orderLine(2, 1)}
+
+	setQuantity := func(quantity int) func(*typs.OrderItem) (*typs.OrderItem, error) {
+		return func(_ *typs.OrderItem) (*typs.OrderItem, error) {
+			if quantity == 0 {
+				return nil, nil
+			}
+			next := orderLine(0, quantity)
+			return &next, nil
+		}
+	}
+
+	for name, test := range map[string]struct {
+		coffeeID int
+		quantity int
+		changed  bool
+		expected string
+	}{
+		"add":       {coffeeID: 3, quantity: 4, changed: true, expected: "1x2 2x1 3x4"},
+		"update":    {coffeeID: 2, quantity: 5, changed: true, expected: "1x2 2x5"},
+		"remove":    {coffeeID: 1, quantity: 0, changed: true, expected: "2x1"},
+		"unchanged": {coffeeID: 1, quantity: 2, changed: false, expected: "1x2 2x1"},
+		"absent":    {coffeeID: 3, quantity: 0, changed: false, expected: "1x2 2x1"},
+	} {
+		edited, changed, err := editOrderLine(items, test.coffeeID, setQuantity(test.quantity))
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %s", name, err)
+		}
+		if changed != test.changed {
+			t.Errorf("%s: expected changed %t, got %t", name, test.changed, changed)
+		}
+		if got := formatOrderLines(edited); got != test.expected {
+			t.Errorf("%s: expected items %q, got %q", name, test.expected, got)
+		}
+	}
+}
+
+func TestClientEditOrderLineRetriesChangedOrder(t *testing.T) {
+	var (
+		mu      sync.Mutex
+		version = 1
+		items   = []typs.OrderItem{orderLine(1, 2)}
+		puts    int
+	)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		if r.Method == http.MethodPut {
+			puts++
+			// Another client adds a line before the first update lands.
+			if puts == 1 {
+				items = append(items, orderLine(2, 1))
+				version++
+			}
+			if r.Header.Get("If-Match") != fmt.Sprintf(`"v%d"`, version) {
+				w.WriteHeader(http.StatusPreconditionFailed)
+				w.Write([]byte(`{"message":"order was modified"}`))
+				return
+			}
+			json.NewDecoder(r.Body).Decode(&items)
+			version++
+		}
+
+		w.Header().Set("ETag", fmt.Sprintf(`"v%d"`, version))
+		json.NewEncoder(w).Encode(typs.Order{ID: 1, Items: items})
+	}))
+	defer server.Close()
+
+	client := newTestClient(server.URL)
+
+	order, err := client.EditOrderLine(context.Background(), "1", 3, func(line *typs.OrderItem) (*typs.OrderItem, error) {
+		next := orderLine(3, 1)
+		return &next, nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if puts != 2 {
+		t.Errorf("expected the edit to be retried once, got %d updates", puts)
+	}
+	if got := formatOrderLines(order.Items); got != "1x2 2x1 3x1" {
+		t.Errorf("expected the line to be added to the changed order, got %q", got)
+	}
+}
+
+func TestParseOrderItemID(t *testing.T) {
+	orderID, coffeeID, err := parseOrderItemID("12:3")
+	if err != nil || orderID != "12" || coffeeID != 3 {
+		t.Errorf("expected order 12 and coffee 3, got %q, %d, %v", orderID, coffeeID, err)
+	}
+
+	for _, id := range []string{"12", ":3", "12:espresso"} {
+		if _, _, err := parseOrderItemID(id); err == nil {
+			t.Errorf("%q: expected an error", id)
+		}
+	}
+}
+
+// formatOrderLines formats items as "<coffee id>x<quantity>" pairs.
+func formatOrderLines(items []typs.OrderItem) string {
+	formatted := ""
+	for i, item := range items {
+		if i > 0 {
+			formatted += " "
+		}
+		formatted += fmt.Sprintf("%dx%d", item.Coffee.ID, item.Quantity)
+	}
+
+	return formatted
+}
@@ -2,46 +2,81 @@ package fsd
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
 	"strconv"
+	"strings"
 	"time"
 
 	typs "github.com/gofsd/fsd-types"
+	"github.com/hashicorp/terraform-plugin-framework-jsontypes/jsontypes"
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Values of the on_destroy attribute of fsd_order.
+const (
+	orderOnDestroyDelete  = "delete"
+	orderOnDestroyCancel  = "cancel"
+	orderOnDestroyAbandon = "abandon"
 )
 
 // Ensure the implementation satisfies the expected interfaces.
 var (
-	_ resource.Resource                = &orderResource{}
-	_ resource.ResourceWithConfigure   = &orderResource{}
-	_ resource.ResourceWithImportState = &orderResource{}
+	_ resource.Resource                 = &orderResource{}
+	_ resource.ResourceWithConfigure    = &orderResource{}
+	_ resource.ResourceWithImportState  = &orderResource{}
+	_ resource.ResourceWithModifyPlan   = &orderResource{}
+	_ resource.ResourceWithUpgradeState = &orderResource{}
 )
 
 // orderResourceModel maps the resource schema data.
 type orderResourceModel struct {
-	ID          types.String     `tfsdk:"id"`
-	Items       []orderItemModel `tfsdk:"items"`
-	LastUpdated types.String     `tfsdk:"last_updated"`
+	ID                 types.String         `tfsdk:"id"`
+	Items              []orderItemModel     `tfsdk:"items"`
+	Labels             types.Map            `tfsdk:"labels"`
+	LabelsAll          types.Map            `tfsdk:"labels_all"`
+	Budget             *orderBudgetModel    `tfsdk:"budget"`
+	OnDestroy          types.String         `tfsdk:"on_destroy"`
+	DeletionProtection types.Bool           `tfsdk:"deletion_protection"`
+	Status             types.String         `tfsdk:"status"`
+	WaitForStatus      types.String         `tfsdk:"wait_for_status"`
+	CreatedAt          types.String         `tfsdk:"created_at"`
+	UpdatedAt          types.String         `tfsdk:"updated_at"`
+	LastUpdated        types.String         `tfsdk:"last_updated"`
+	RawJSON            jsontypes.Normalized `tfsdk:"raw_json"`
+	IdempotencyKey     types.String         `tfsdk:"idempotency_key"`
+	Timeouts           timeouts.Value       `tfsdk:"timeouts"`
 }
 
 // orderItemModel maps order item data.
 type orderItemModel struct {
-	Coffee   orderItemCoffeeModel `tfsdk:"coffee"`
-	Quantity types.Int64          `tfsdk:"quantity"`
+	Coffee        orderItemCoffeeModel `tfsdk:"coffee"`
+	Quantity      types.Int64          `tfsdk:"quantity"`
+	PriceAtOrder  moneyValue           `tfsdk:"price_at_order"`
+	ExpectedPrice moneyValue           `tfsdk:"expected_price"`
 }
 
 // orderItemCoffeeModel maps coffee order item data.
 type orderItemCoffeeModel struct {
-	ID          types.Int64   `tfsdk:"id"`
-	Name        types.String  `tfsdk:"name"`
-	Teaser      types.String  `tfsdk:"teaser"`
-	Description types.String  `tfsdk:"description"`
-	Price       types.Float64 `tfsdk:"price"`
-	Image       types.String  `tfsdk:"image"`
+	ID             types.Int64  `tfsdk:"id"`
+	Name           types.String `tfsdk:"name"`
+	Teaser         types.String `tfsdk:"teaser"`
+	Description    types.String `tfsdk:"description"`
+	Price          moneyValue   `tfsdk:"price"`
+	PriceConverted moneyValue   `tfsdk:"price_converted"`
+	Currency       types.String `tfsdk:"currency"`
+	Image          types.String `tfsdk:"image"`
 }
 
 // NewOrderResource is a helper function to simplify the provider implementation.
@@ -51,7 +86,12 @@ func NewOrderResource() resource.Resource {
 
 // orderResource is the resource implementation.
 type orderResource struct {
-	client *typs.Client
+	client        *fsdClient
+	defaultLabels map[string]string
+	converter     *currencyConverter
+	catalog       *catalogCache
+	budget        orderBudget
+	coffeePolicy  coffeePolicy
 }
 
 // Configure adds the provider configured client to the resource.
@@ -60,7 +100,13 @@ func (r *orderResource) Configure(_ context.Context, req resource.ConfigureReque
 		return
 	}
 
-	r.client = req.ProviderData.(*typs.Client)
+	providerData := req.ProviderData.(*fsdProviderData)
+	r.client = providerData.client
+	r.defaultLabels = providerData.defaultLabels
+	r.converter = providerData.converter
+	r.catalog = providerData.catalog
+	r.budget = providerData.budget
+	r.coffeePolicy = providerData.coffeePolicy
 }
 
 // Metadata returns the resource type name.
@@ -69,9 +115,10 @@ func (r *orderResource) Metadata(_ context.Context, req resource.MetadataRequest
 }
 
 // Schema defines the schema for the resource.
-func (r *orderResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+func (r *orderResource) Schema(ctx context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
 	resp.Schema = schema.Schema{
 		Description: "Manages an order.",
+		Version:     1,
 		Attributes: map[string]schema.Attribute{
 			"id": schema.StringAttribute{
 				Description: "Numeric identifier of the order.",
@@ -80,19 +127,118 @@ func (r *orderResource) Schema(_ context.Context, _ resource.SchemaRequest, resp
 					stringplanmodifier.UseStateForUnknown(),
 				},
 			},
-			"last_updated": schema.StringAttribute{
-				Description: "Timestamp of the last Terraform update of the order.",
+			"labels": schema.MapAttribute{
+				Description: "Labels of the order, such as team or cost center. Merged over the provider default_labels.",
+				ElementType: types.StringType,
+				Optional:    true,
+			},
+			"labels_all": schema.MapAttribute{
+				Description: "Effective labels of the order: the provider default_labels merged with labels.",
+				ElementType: types.StringType,
+				Computed:    true,
+			},
+			"budget": schema.SingleNestedAttribute{
+				Description: "Spend limits for this order, overriding the provider max_order_total, " +
+					"max_item_quantity and budget_enforcement. Checked at plan time using catalog prices.",
+				Optional: true,
+				Attributes: map[string]schema.Attribute{
+					"max_order_total": schema.NumberAttribute{
+						Description: "Highest total, in the provider currency, that the order may cost.",
+						Optional:    true,
+					},
+					"max_item_quantity": schema.Int64Attribute{
+						Description: "Highest quantity of a single item of the order.",
+						Optional:    true,
+					},
+					"enforcement": schema.StringAttribute{
+						Description: "How the limits are enforced: \"strict\" fails the plan, \"advisory\" only warns.",
+						Optional:    true,
+						Validators: []validator.String{
+							stringOneOf(budgetEnforcementStrict, budgetEnforcementAdvisory),
+						},
+					},
+				},
+			},
+			"on_destroy": schema.StringAttribute{
+				Description: "What happens to the order when it is destroyed: \"delete\" removes it, " +
+					"\"cancel\" cancels it but keeps it in the fsd API, and \"abandon\" only removes it " +
+					"from the Terraform state. Defaults to \"delete\".",
+				Optional: true,
+				Computed: true,
+				Default:  stringdefault.StaticString(orderOnDestroyDelete),
+				Validators: []validator.String{
+					stringOneOf(orderOnDestroyDelete, orderOnDestroyCancel, orderOnDestroyAbandon),
+				},
+			},
+			"deletion_protection": schema.BoolAttribute{
+				Description: "Whether Terraform refuses to destroy the order. Must be set to false " +
+					"and applied before the order can be destroyed. Defaults to false.",
+				Optional: true,
+				Computed: true,
+				Default:  booldefault.StaticBool(false),
+			},
+			"status": schema.StringAttribute{
+				Description: "Lifecycle status of the order, such as \"pending\" or \"fulfilled\".",
 				Computed:    true,
 			},
+			"wait_for_status": schema.StringAttribute{
+				Description: "Status the order must reach, such as \"fulfilled\", before creating or updating it completes. " +
+					"Terraform does not wait unless set. The create and update timeouts of the timeouts block limit the wait, " +
+					"which defaults to 20m.",
+				Optional: true,
+			},
+			"created_at": schema.StringAttribute{
+				Description: "RFC 3339 timestamp of the order creation, as recorded by the fsd API.",
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"updated_at": schema.StringAttribute{
+				Description: "RFC 3339 timestamp of the last update of the order, as recorded by the fsd API.",
+				Computed:    true,
+			},
+			"last_updated": schema.StringAttribute{
+				Description:        "Deprecated alias of updated_at.",
+				DeprecationMessage: "Use updated_at instead. The last_updated attribute will be removed in a future release.",
+				Computed:           true,
+			},
+			"raw_json": schema.StringAttribute{
+				Description: "The order as returned by the fsd API, including fields the provider does not model yet. " +
+					"Decode it with jsondecode.",
+				CustomType: jsontypes.NormalizedType{},
+				Computed:   true,
+			},
+			"idempotency_key": schema.StringAttribute{
+				Description: "Key the order was created with. Retrying a creation that failed without a response " +
+					"resends it, so the fsd API returns the order created by the first attempt instead of a second one.",
+				Computed: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
 			"items": schema.ListNestedAttribute{
-				Description: "List of items in the order.",
-				Required:    true,
+				Description: "List of items in the order. Setting it removes any item of the order it does not list, " +
+					"including items added by fsd_order_item. Omit it to leave the items of the order to fsd_order_item resources.",
+				Optional: true,
 				NestedObject: schema.NestedAttributeObject{
 					Attributes: map[string]schema.Attribute{
 						"quantity": schema.Int64Attribute{
 							Description: "Count of this item in the order.",
 							Required:    true,
 						},
+						"price_at_order": schema.NumberAttribute{
+							Description: "Price of the coffee when it was added to the order. " +
+								"Refreshing the order warns when the current price differs.",
+							CustomType: moneyType{},
+							Computed:   true,
+						},
+						"expected_price": schema.NumberAttribute{
+							Description: "Highest catalog price of the coffee, in the fsd API currency, that the plan accepts. " +
+								"Planning fails if the catalog price rose past it.",
+							CustomType: moneyType{},
+							Optional:   true,
+						},
 						"coffee": schema.SingleNestedAttribute{
 							Description: "Coffee item in the order.",
 							Required:    true,
@@ -113,8 +259,18 @@ func (r *orderResource) Schema(_ context.Context, _ resource.SchemaRequest, resp
 									Description: "Product description of the coffee.",
 									Computed:    true,
 								},
-								"price": schema.Float64Attribute{
+								"price": schema.NumberAttribute{
 									Description: "Suggested cost of the coffee.",
+									CustomType:  moneyType{},
+									Computed:    true,
+								},
+								"price_converted": schema.NumberAttribute{
+									Description: "Suggested cost of the coffee in the provider currency. Equal to price unless the provider currency is set.",
+									CustomType:  moneyType{},
+									Computed:    true,
+								},
+								"currency": schema.StringAttribute{
+									Description: "Currency of price_converted, or null if prices are not converted.",
 									Computed:    true,
 								},
 								"image": schema.StringAttribute{
@@ -127,7 +283,87 @@ func (r *orderResource) Schema(_ context.Context, _ resource.SchemaRequest, resp
 				},
 			},
 		},
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.Block(ctx, timeouts.Opts{
+				Create:            true,
+				Update:            true,
+				CreateDescription: "How long creating the order waits for wait_for_status. Defaults to 20m.",
+				UpdateDescription: "How long updating the order waits for wait_for_status. Defaults to 20m.",
+			}),
+		},
+	}
+}
+
+// UpgradeState upgrades the state of earlier schema versions.
+func (r *orderResource) UpgradeState(_ context.Context) map[int64]resource.StateUpgrader {
+	return map[int64]resource.StateUpgrader{
+		// Version 0 stored prices as float64.
+		0: {StateUpgrader: upgradeFloatPricesV0},
+	}
+}
+
+// ModifyPlan rejects destroying a protected order, merges the provider
+// default labels, checks the items against the allowed coffees and the
+// budget, keeps the price snapshots of updated items and reports the cost
+// change of updates. The idempotency key of a new order stays unknown, as
+// Terraform plans a resource again at apply and a key generated here would
+// differ between the two plans; Create generates it. Only the key of a
+// creation that failed without a response is carried over from private
+// state into the plan of the replacement, so Create resends it and the API
+// returns that order instead of creating a second one.
+func (r *orderResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.Plan.Raw.IsNull() {
+		var state orderResourceModel
+		diags := req.State.Get(ctx, &state)
+		resp.Diagnostics.Append(diags...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+
+		if state.DeletionProtection.ValueBool() {
+			addDeletionProtectionError(&resp.Diagnostics, state.ID.ValueString())
+		}
+		return
+	}
+
+	modifyPlanLabels(ctx, r.defaultLabels, req, resp)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	modifyPlanCoffeePolicy(ctx, r.coffeePolicy, r.catalog, req, resp)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	r.checkExpectedPrices(ctx, req, resp)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	r.checkBudget(ctx, req, resp)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !req.State.Raw.IsNull() {
+		modifyPlanPriceSnapshots(ctx, req, resp)
+		r.warnCostChange(ctx, req, resp)
+		return
+	}
+
+	idempotencyKey, diags := getPrivateString(ctx, req.Private, privateStateIdempotencyKey)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
 	}
+
+	if idempotencyKey == "" {
+		return
+	}
+
+	diags = resp.Plan.SetAttribute(ctx, path.Root("idempotency_key"), idempotencyKey)
+	resp.Diagnostics.Append(diags...)
 }
 
 // Create a new resource
@@ -141,9 +377,9 @@ func (r *orderResource) Create(ctx context.Context, req resource.CreateRequest,
 	}
 
 	// Generate API request body from plan
-	var items []typs.OrderItem
+	fsdItems := []typs.OrderItem{}
 	for _, item := range plan.Items {
-		items = append(items, typs.OrderItem{
+		fsdItems = append(fsdItems, typs.OrderItem{
 			Coffee: typs.Coffee{
 				ID: int(item.Coffee.ID.ValueInt64()),
 			},
@@ -151,32 +387,85 @@ func (r *orderResource) Create(ctx context.Context, req resource.CreateRequest,
 		})
 	}
 
+	waitTimeout, diags := plan.Timeouts.Create(ctx, orderWaitDefaultTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Generate the idempotency key, unless the plan carries over the key of
+	// a creation that failed without a response. Persist it before sending
+	// the order, so the plan replacing an order whose creation fails the
+	// same way reuses it.
+	idempotencyKey := plan.IdempotencyKey.ValueString()
+	if idempotencyKey == "" {
+		var err error
+		idempotencyKey, err = newIdempotencyKey()
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Error creating order",
+				"Could not generate an idempotency key for the order: "+err.Error(),
+			)
+			return
+		}
+		plan.IdempotencyKey = types.StringValue(idempotencyKey)
+	}
+	diags = setPrivateString(ctx, resp.Private, privateStateIdempotencyKey, idempotencyKey)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	// Create new order
-	order, err := r.client.CreateOrder(items)
+	order, etag, err := r.client.CreateOrder(ctx, fsdItems, idempotencyKey)
 	if err != nil {
-		resp.Diagnostics.AddError(
+		addAPIErrorDiagnostics(
+			&resp.Diagnostics,
 			"Error creating order",
-			"Could not create order, unexpected error: "+err.Error(),
+			"Could not create order",
+			err,
 		)
+
+		// Without a response the order may exist. Save it without an id
+		// so Terraform replaces it on the next apply, whose plan takes
+		// over the idempotency key kept in private state.
+		if isAmbiguousError(err) {
+			diags = resp.State.Set(ctx, pendingOrderState(plan))
+			resp.Diagnostics.Append(diags...)
+		}
 		return
 	}
 
+	// The key is no longer needed once the order exists.
+	diags = setPrivateString(ctx, resp.Private, privateStateIdempotencyKey, "")
+	resp.Diagnostics.Append(diags...)
+
+	// The order is saved even if waiting fails, so Terraform taints it.
+	order, etag, err = r.waitForStatus(ctx, plan, waitTimeout, order, etag)
+	if err != nil {
+		addAPIErrorDiagnostics(
+			&resp.Diagnostics,
+			"Error Waiting for fsd Order",
+			"Order ID "+strconv.Itoa(order.ID)+" did not reach status "+plan.WaitForStatus.ValueString(),
+			err,
+		)
+	}
+
+	diags = setPrivateString(ctx, resp.Private, privateStateETag, etag)
+	resp.Diagnostics.Append(diags...)
+
 	// Map response body to schema and populate Computed attribute values
 	plan.ID = types.StringValue(strconv.Itoa(order.ID))
-	for orderItemIndex, orderItem := range order.Items {
-		plan.Items[orderItemIndex] = orderItemModel{
-			Coffee: orderItemCoffeeModel{
-				ID:          types.Int64Value(int64(orderItem.Coffee.ID)),
-				Name:        types.StringValue(orderItem.Coffee.Name),
-				Teaser:      types.StringValue(orderItem.Coffee.Teaser),
-				Description: types.StringValue(orderItem.Coffee.Description),
-				Price:       types.Float64Value(orderItem.Coffee.Price),
-				Image:       types.StringValue(orderItem.Coffee.Image),
-			},
-			Quantity: types.Int64Value(int64(orderItem.Quantity)),
-		}
+	if plan.Items != nil {
+		items := orderItemsFromAPI(order.Items, r.converter)
+		keepItemSnapshots(items, plan.Items, nil)
+		plan.Items = items
 	}
-	plan.LastUpdated = types.StringValue(time.Now().Format(time.RFC850))
+	plan.Status = optionalStringValue(order.Status)
+	plan.CreatedAt = timestampValue(order.CreatedAt)
+	plan.UpdatedAt = timestampValue(order.UpdatedAt)
+	plan.LastUpdated = plan.UpdatedAt
+	plan.RawJSON = rawJSONValue(order.Raw)
 
 	// Set state to fully populated data
 	diags = resp.State.Set(ctx, plan)
@@ -196,31 +485,44 @@ func (r *orderResource) Read(ctx context.Context, req resource.ReadRequest, resp
 		return
 	}
 
+	// An order whose creation outcome is unknown has nothing to refresh.
+	if state.ID.IsNull() {
+		return
+	}
+
 	// Get refreshed order value from fsd
-	order, err := r.client.GetOrder(state.ID.ValueString())
+	order, etag, err := r.client.GetOrder(ctx, state.ID.ValueString())
 	if err != nil {
-		resp.Diagnostics.AddError(
+		addAPIErrorDiagnostics(
+			&resp.Diagnostics,
 			"Error Reading fsd Order",
-			"Could not read fsd order ID "+state.ID.ValueString()+": "+err.Error(),
+			"Could not read fsd order ID "+state.ID.ValueString(),
+			err,
 		)
 		return
 	}
 
-	// Overwrite items with refreshed state
-	state.Items = []orderItemModel{}
-	for _, item := range order.Items {
-		state.Items = append(state.Items, orderItemModel{
-			Coffee: orderItemCoffeeModel{
-				ID:          types.Int64Value(int64(item.Coffee.ID)),
-				Name:        types.StringValue(item.Coffee.Name),
-				Teaser:      types.StringValue(item.Coffee.Teaser),
-				Description: types.StringValue(item.Coffee.Description),
-				Price:       types.Float64Value(item.Coffee.Price),
-				Image:       types.StringValue(item.Coffee.Image),
-			},
-			Quantity: types.Int64Value(int64(item.Quantity)),
-		})
+	// Remember the version that was read, so later changes are only
+	// applied to it.
+	diags = setPrivateString(ctx, resp.Private, privateStateETag, etag)
+	resp.Diagnostics.Append(diags...)
+
+	// Overwrite items, status and timestamps with refreshed state, keeping
+	// the price snapshots taken when the items were ordered. Items left to
+	// fsd_order_item resources stay null.
+	if state.Items != nil {
+		resp.Diagnostics.Append(unlistedItemsWarning(state.ID.ValueString(), state.Items, order.Items)...)
+
+		items := orderItemsFromAPI(order.Items, r.converter)
+		keepItemSnapshots(items, state.Items, state.Items)
+		resp.Diagnostics.Append(priceDriftWarnings(items)...)
+		state.Items = items
 	}
+	state.Status = optionalStringValue(order.Status)
+	state.CreatedAt = timestampValue(order.CreatedAt)
+	state.UpdatedAt = timestampValue(order.UpdatedAt)
+	state.LastUpdated = state.UpdatedAt
+	state.RawJSON = rawJSONValue(order.Raw)
 
 	// Set refreshed state
 	diags = resp.State.Set(ctx, &state)
@@ -239,6 +541,14 @@ func (r *orderResource) Update(ctx context.Context, req resource.UpdateRequest,
 		return
 	}
 
+	// Retrieve prior state for the price snapshots of unchanged items
+	var state orderResourceModel
+	diags = req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	// Generate API request body from plan
 	var fsdItems []typs.OrderItem
 	for _, item := range plan.Items {
@@ -250,43 +560,76 @@ func (r *orderResource) Update(ctx context.Context, req resource.UpdateRequest,
 		})
 	}
 
-	// Update existing order
-	_, err := r.client.UpdateOrder(plan.ID.ValueString(), fsdItems)
-	if err != nil {
-		resp.Diagnostics.AddError(
-			"Error Updating fsd Order",
-			"Could not update order, unexpected error: "+err.Error(),
-		)
+	waitTimeout, diags := plan.Timeouts.Update(ctx, orderWaitDefaultTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
 		return
 	}
 
+	etag, diags := getPrivateString(ctx, req.Private, privateStateETag)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Update existing order, unless it changed since it was last read.
+	// Items left to fsd_order_item resources are not replaced.
+	if plan.Items != nil {
+		_, _, err := r.client.UpdateOrder(ctx, plan.ID.ValueString(), fsdItems, etag)
+		if err != nil {
+			addAPIErrorDiagnostics(
+				&resp.Diagnostics,
+				"Error Updating fsd Order",
+				"Could not update order",
+				err,
+			)
+			return
+		}
+	}
+
 	// Fetch updated items from GetOrder as UpdateOrder items are not
 	// populated.
-	order, err := r.client.GetOrder(plan.ID.ValueString())
+	order, etag, err := r.client.GetOrder(ctx, plan.ID.ValueString())
 	if err != nil {
-		resp.Diagnostics.AddError(
+		addAPIErrorDiagnostics(
+			&resp.Diagnostics,
 			"Error Reading fsd Order",
-			"Could not read fsd order ID "+plan.ID.ValueString()+": "+err.Error(),
+			"Could not read fsd order ID "+plan.ID.ValueString(),
+			err,
 		)
 		return
 	}
 
-	// Update resource state with updated items and timestamp
-	plan.Items = []orderItemModel{}
-	for _, item := range order.Items {
-		plan.Items = append(plan.Items, orderItemModel{
-			Coffee: orderItemCoffeeModel{
-				ID:          types.Int64Value(int64(item.Coffee.ID)),
-				Name:        types.StringValue(item.Coffee.Name),
-				Teaser:      types.StringValue(item.Coffee.Teaser),
-				Description: types.StringValue(item.Coffee.Description),
-				Price:       types.Float64Value(item.Coffee.Price),
-				Image:       types.StringValue(item.Coffee.Image),
-			},
-			Quantity: types.Int64Value(int64(item.Quantity)),
-		})
+	// The order is saved even if waiting fails, so Terraform taints it.
+	order, etag, err = r.waitForStatus(ctx, plan, waitTimeout, order, etag)
+	if err != nil {
+		addAPIErrorDiagnostics(
+			&resp.Diagnostics,
+			"Error Waiting for fsd Order",
+			"Order ID "+plan.ID.ValueString()+" did not reach status "+plan.WaitForStatus.ValueString(),
+			err,
+		)
+	}
+
+	diags = setPrivateString(ctx, resp.Private, privateStateETag, etag)
+	resp.Diagnostics.Append(diags...)
+
+	// Update resource state with updated items, status and timestamp
+	if plan.Items != nil {
+		items := orderItemsFromAPI(order.Items, r.converter)
+		keepItemSnapshots(items, plan.Items, state.Items)
+		plan.Items = items
+	}
+	plan.Status = optionalStringValue(order.Status)
+	plan.CreatedAt = timestampValue(order.CreatedAt)
+	plan.UpdatedAt = timestampValue(order.UpdatedAt)
+	plan.LastUpdated = plan.UpdatedAt
+	plan.RawJSON = rawJSONValue(order.Raw)
+
+	// Imported orders were not created with a key.
+	if plan.IdempotencyKey.IsUnknown() {
+		plan.IdempotencyKey = types.StringNull()
 	}
-	plan.LastUpdated = types.StringValue(time.Now().Format(time.RFC850))
 
 	diags = resp.State.Set(ctx, plan)
 	resp.Diagnostics.Append(diags...)
@@ -304,12 +647,57 @@ func (r *orderResource) Delete(ctx context.Context, req resource.DeleteRequest,
 		return
 	}
 
-	// Delete existing order
-	err := r.client.DeleteOrder(state.ID.ValueString())
+	// An order whose creation outcome is unknown is only removed from state.
+	if state.ID.IsNull() {
+		return
+	}
+
+	if state.DeletionProtection.ValueBool() {
+		addDeletionProtectionError(&resp.Diagnostics, state.ID.ValueString())
+		return
+	}
+
+	if state.OnDestroy.ValueString() == orderOnDestroyAbandon {
+		tflog.Info(ctx, "Abandoning fsd order, leaving it in the fsd API", map[string]interface{}{
+			"order_id": state.ID.ValueString(),
+		})
+		return
+	}
+
+	etag, diags := getPrivateString(ctx, req.Private, privateStateETag)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Items left to fsd_order_item resources change the order, and thus
+	// its ETag, while they are destroyed alongside it.
+	if state.Items == nil {
+		etag = ""
+	}
+
+	// Cancel existing order, unless it changed since it was last read
+	if state.OnDestroy.ValueString() == orderOnDestroyCancel {
+		err := r.client.CancelOrder(ctx, state.ID.ValueString(), etag)
+		if err != nil {
+			addAPIErrorDiagnostics(
+				&resp.Diagnostics,
+				"Error Cancelling fsd Order",
+				"Could not cancel order",
+				err,
+			)
+		}
+		return
+	}
+
+	// Delete existing order, unless it changed since it was last read
+	err := r.client.DeleteOrder(ctx, state.ID.ValueString(), etag)
 	if err != nil {
-		resp.Diagnostics.AddError(
+		addAPIErrorDiagnostics(
+			&resp.Diagnostics,
 			"Error Deleting fsd Order",
-			"Could not delete order, unexpected error: "+err.Error(),
+			"Could not delete order",
+			err,
 		)
 		return
 	}
@@ -318,4 +706,314 @@ func (r *orderResource) Delete(ctx context.Context, req resource.DeleteRequest,
 func (r *orderResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
 	// Retrieve import ID and save to id attribute
 	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+
+	// Attributes that only exist in Terraform start from their defaults.
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("on_destroy"), orderOnDestroyDelete)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("deletion_protection"), false)...)
+
+	// Import the items, which the next plan drops if they are left to
+	// fsd_order_item resources.
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("items"), []orderItemModel{})...)
+
+	labelsAll, diags := defaultLabelsValue(ctx, r.defaultLabels)
+	resp.Diagnostics.Append(diags...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("labels_all"), labelsAll)...)
+}
+
+// checkBudget reports planned items that exceed the budget of the order.
+func (r *orderResource) checkBudget(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	var budgetModel *orderBudgetModel
+	diags := req.Plan.GetAttribute(ctx, path.Root("budget"), &budgetModel)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	budget, err := r.budget.withOverrides(budgetModel)
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("budget").AtName("max_order_total"),
+			"Invalid fsd Budget",
+			"The max_order_total value is not a valid amount: "+err.Error(),
+		)
+		return
+	}
+
+	items, diags := plannedOrderItems(ctx, req)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() || items == nil {
+		return
+	}
+
+	var coffees []typs.Coffee
+	if budget.needsCatalog() && r.catalog != nil {
+		coffees, err = r.catalog.GetCoffees(ctx)
+		if err != nil {
+			addAPIErrorDiagnostics(
+				&resp.Diagnostics,
+				"Error Checking fsd Order Budget",
+				"Could not read the fsd coffee catalog",
+				err,
+			)
+			return
+		}
+	}
+
+	resp.Diagnostics.Append(budget.check(items, coffees, r.converter)...)
+}
+
+// checkExpectedPrices fails the plan for items whose coffee costs more than
+// their expected_price.
+func (r *orderResource) checkExpectedPrices(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	items, diags := plannedOrderItems(ctx, req)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() || !hasExpectedPrices(items) || r.catalog == nil {
+		return
+	}
+
+	coffees, err := r.catalog.GetCoffees(ctx)
+	if err != nil {
+		addAPIErrorDiagnostics(
+			&resp.Diagnostics,
+			"Error Checking fsd Coffee Prices",
+			"Could not read the fsd coffee catalog",
+			err,
+		)
+		return
+	}
+
+	resp.Diagnostics.Append(checkExpectedPrices(items, coffees)...)
+}
+
+// warnCostChange reports how an update changes the cost of the order at
+// catalog prices, so the money impact shows in the plan output.
+func (r *orderResource) warnCostChange(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if r.catalog == nil {
+		return
+	}
+
+	var state orderResourceModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	items, diags := plannedOrderItems(ctx, req)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() || items == nil {
+		return
+	}
+
+	coffees, err := r.catalog.GetCoffees(ctx)
+	if err != nil {
+		addAPIErrorDiagnostics(
+			&resp.Diagnostics,
+			"Error Calculating fsd Order Cost",
+			"Could not read the fsd coffee catalog",
+			err,
+		)
+		return
+	}
+
+	oldTotal, ok := orderTotal(state.Items, coffees, r.converter)
+	if !ok {
+		return
+	}
+	newTotal, ok := orderTotal(items, coffees, r.converter)
+	if !ok || newTotal.Equal(oldTotal) {
+		return
+	}
+
+	delta := newTotal.Sub(oldTotal)
+	sign := ""
+	if delta.IsPositive() {
+		sign = "+"
+	}
+
+	resp.Diagnostics.AddAttributeWarning(
+		path.Root("items"),
+		"fsd Order Cost Change",
+		fmt.Sprintf("Order ID %s changes from %s to %s (%s%s) at current catalog prices.",
+			state.ID.ValueString(),
+			formatAmount(oldTotal, r.converter),
+			formatAmount(newTotal, r.converter),
+			sign, formatAmount(delta, r.converter)),
+	)
+}
+
+// plannedOrderItems returns the planned items, or nil if the list is
+// unknown or null. Unknown items are returned as empty items.
+func plannedOrderItems(ctx context.Context, req resource.ModifyPlanRequest) ([]orderItemModel, diag.Diagnostics) {
+	var itemsList types.List
+	diags := req.Plan.GetAttribute(ctx, path.Root("items"), &itemsList)
+	if diags.HasError() || itemsList.IsUnknown() || itemsList.IsNull() {
+		return nil, diags
+	}
+
+	items := []orderItemModel{}
+	diags.Append(itemsList.ElementsAs(ctx, &items, true)...)
+
+	return items, diags
+}
+
+// waitForStatus waits up to timeout until the order reaches the planned
+// wait_for_status, if one is set, and returns the order and ETag as last
+// read.
+func (r *orderResource) waitForStatus(ctx context.Context, plan orderResourceModel, timeout time.Duration, order *apiOrder, etag string) (*apiOrder, string, error) {
+	status := plan.WaitForStatus.ValueString()
+	if plan.WaitForStatus.IsNull() || order.Status == status {
+		return order, etag, nil
+	}
+
+	waited, waitedETag, err := newOrderStatusWaiter(r.client).wait(ctx, strconv.Itoa(order.ID), status, timeout)
+	if waited == nil {
+		return order, etag, err
+	}
+
+	return waited, waitedETag, err
+}
+
+// addDeletionProtectionError reports that a protected order cannot be
+// destroyed.
+func addDeletionProtectionError(diags *diag.Diagnostics, orderID string) {
+	diags.AddError(
+		"fsd Order Is Protected From Deletion",
+		"Order ID "+orderID+" has deletion_protection enabled. "+
+			"Set deletion_protection = false and apply the change before destroying or replacing the order.",
+	)
+}
+
+// pendingOrderState returns the state of an order whose creation failed
+// without a response: the planned items with every computed value null.
+func pendingOrderState(plan orderResourceModel) orderResourceModel {
+	state := orderResourceModel{
+		ID:                 types.StringNull(),
+		Labels:             plan.Labels,
+		LabelsAll:          plan.LabelsAll,
+		Budget:             plan.Budget,
+		OnDestroy:          plan.OnDestroy,
+		DeletionProtection: plan.DeletionProtection,
+		Status:             types.StringNull(),
+		WaitForStatus:      plan.WaitForStatus,
+		Timeouts:           plan.Timeouts,
+		CreatedAt:          types.StringNull(),
+		UpdatedAt:          types.StringNull(),
+		LastUpdated:        types.StringNull(),
+		RawJSON:            jsontypes.NewNormalizedNull(),
+		IdempotencyKey:     plan.IdempotencyKey,
+	}
+
+	if plan.Items != nil {
+		state.Items = []orderItemModel{}
+	}
+	for _, item := range plan.Items {
+		state.Items = append(state.Items, orderItemModel{
+			Coffee: orderItemCoffeeModel{
+				ID:             item.Coffee.ID,
+				Name:           types.StringNull(),
+				Teaser:         types.StringNull(),
+				Description:    types.StringNull(),
+				Price:          moneyNull(),
+				PriceConverted: moneyNull(),
+				Currency:       types.StringNull(),
+				Image:          types.StringNull(),
+			},
+			Quantity:      item.Quantity,
+			PriceAtOrder:  moneyNull(),
+			ExpectedPrice: item.ExpectedPrice,
+		})
+	}
+
+	return state
+}
+
+// unlistedItemsWarning warns about items of an order that are missing from
+// the items it was last saved with, such as items added by fsd_order_item,
+// which the next apply removes. The empty items of an imported order are not
+// checked.
+func unlistedItemsWarning(orderID string, listed []orderItemModel, items []typs.OrderItem) diag.Diagnostics {
+	var diags diag.Diagnostics
+	if len(listed) == 0 {
+		return diags
+	}
+
+	known := make(map[int64]bool, len(listed))
+	for _, item := range listed {
+		known[item.Coffee.ID.ValueInt64()] = true
+	}
+
+	var unlisted []string
+	for _, item := range items {
+		if !known[int64(item.Coffee.ID)] {
+			unlisted = append(unlisted, strconv.Itoa(item.Coffee.ID))
+		}
+	}
+	if len(unlisted) == 0 {
+		return diags
+	}
+
+	diags.AddAttributeWarning(
+		path.Root("items"),
+		"fsd Order Has Unlisted Items",
+		fmt.Sprintf("Order ID %s has items for coffee IDs %s that its items attribute does not list, "+
+			"so the next apply removes them. If fsd_order_item resources manage these items, "+
+			"remove the items attribute from the fsd_order resource.", orderID, strings.Join(unlisted, ", ")),
+	)
+
+	return diags
+}
+
+// orderItemsFromAPI maps fsd order items to item models, converting prices
+// with converter. The price snapshots are left null.
+func orderItemsFromAPI(items []typs.OrderItem, converter *currencyConverter) []orderItemModel {
+	models := []orderItemModel{}
+	for _, item := range items {
+		models = append(models, orderItemModel{
+			Coffee: orderItemCoffeeModel{
+				ID:             types.Int64Value(int64(item.Coffee.ID)),
+				Name:           types.StringValue(item.Coffee.Name),
+				Teaser:         types.StringValue(item.Coffee.Teaser),
+				Description:    types.StringValue(item.Coffee.Description),
+				Price:          moneyFromFloat64(item.Coffee.Price),
+				PriceConverted: converter.convert(item.Coffee.Price),
+				Currency:       converter.currencyValue(),
+				Image:          types.StringValue(item.Coffee.Image),
+			},
+			Quantity:      types.Int64Value(int64(item.Quantity)),
+			PriceAtOrder:  moneyNull(),
+			ExpectedPrice: moneyNull(),
+		})
+	}
+
+	return models
+}
+
+// timestampValue formats an API timestamp as RFC 3339, or returns null if
+// the API did not provide one.
+func timestampValue(t time.Time) types.String {
+	if t.IsZero() {
+		return types.StringNull()
+	}
+
+	return types.StringValue(t.Format(time.RFC3339))
+}
+
+// rawJSONValue returns a JSON object of the API as a raw_json value, or null
+// if the API did not provide one.
+func rawJSONValue(raw json.RawMessage) jsontypes.Normalized {
+	if len(raw) == 0 {
+		return jsontypes.NewNormalizedNull()
+	}
+
+	return jsontypes.NewNormalizedValue(string(raw))
+}
+
+// optionalStringValue returns null for a value the API did not provide.
+func optionalStringValue(value string) types.String {
+	if value == "" {
+		return types.StringNull()
+	}
+
+	return types.StringValue(value)
 }
@@ -1,9 +1,24 @@
 package fsd
 
 import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/http/httputil"
+	"net/url"
+	"regexp"
+	"strconv"
+	"sync"
 	"testing"
 
+	"github.com/hashicorp/terraform-plugin-framework/providerserver"
+	fwresource "github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
 )
 
 func TestAccOrderResource(t *testing.T) {
@@ -36,9 +51,16 @@ resource "fsd_order" "test" {
 					resource.TestCheckResourceAttr("fsd_order.test", "items.0.coffee.name", "HCP Aeropress"),
 					resource.TestCheckResourceAttr("fsd_order.test", "items.0.coffee.price", "200"),
 					resource.TestCheckResourceAttr("fsd_order.test", "items.0.coffee.teaser", "Automation in a cup"),
+					resource.TestCheckResourceAttr("fsd_order.test", "items.0.price_at_order", "200"),
+					resource.TestCheckNoResourceAttr("fsd_order.test", "items.0.expected_price"),
 					// Verify dynamic values have any value set in the state.
 					resource.TestCheckResourceAttrSet("fsd_order.test", "id"),
-					resource.TestCheckResourceAttrSet("fsd_order.test", "last_updated"),
+					resource.TestCheckResourceAttr("fsd_order.test", "on_destroy", "delete"),
+					resource.TestCheckResourceAttr("fsd_order.test", "deletion_protection", "false"),
+					resource.TestCheckResourceAttrSet("fsd_order.test", "created_at"),
+					resource.TestCheckResourceAttrSet("fsd_order.test", "updated_at"),
+					resource.TestCheckResourceAttrPair("fsd_order.test", "last_updated", "fsd_order.test", "updated_at"),
+					resource.TestMatchResourceAttr("fsd_order.test", "raw_json", regexp.MustCompile(`"items":`)),
 				),
 			},
 			// ImportState testing
@@ -46,9 +68,8 @@ resource "fsd_order" "test" {
 				ResourceName:      "fsd_order.test",
 				ImportState:       true,
 				ImportStateVerify: true,
-				// The last_updated attribute does not exist in the fsd
-				// API, therefore there is no value for it during import.
-				ImportStateVerifyIgnore: []string{"last_updated"},
+				// The key an order was created with is not kept by the API.
+				ImportStateVerifyIgnore: []string{"idempotency_key"},
 			},
 			// Update and Read testing
 			{
@@ -80,3 +101,345 @@ resource "fsd_order" "test" {
 		},
 	})
 }
+
+func TestAccOrderResourceDeletionProtection(t *testing.T) {
+	config := func(deletionProtection bool) string {
+		return providerConfig + fmt.Sprintf(`
+resource "fsd_order" "test" {
+  on_destroy          = "cancel"
+  deletion_protection = %t
+
+  items = [
+    {
+      coffee = {
+        id = 1
+      }
+      quantity = 1
+    },
+  ]
+}
+`, deletionProtection)
+	}
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: config(true),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("fsd_order.test", "on_destroy", "cancel"),
+					resource.TestCheckResourceAttr("fsd_order.test", "deletion_protection", "true"),
+				),
+			},
+			// Destroying a protected order fails at plan time
+			{
+				Config:      config(true),
+				Destroy:     true,
+				ExpectError: regexp.MustCompile(`deletion_protection enabled`),
+			},
+			// Turning protection off allows the order to be cancelled
+			{
+				Config: config(false),
+				Check:  resource.TestCheckResourceAttr("fsd_order.test", "deletion_protection", "false"),
+			},
+		},
+	})
+}
+
+func TestAccOrderResourceDroppedCreateResponse(t *testing.T) {
+	upstream, err := url.Parse("http://localhost:19090")
+	if err != nil {
+		t.Fatalf("unexpected error parsing API URL: %s", err)
+	}
+	proxy := httputil.NewSingleHostReverseProxy(upstream)
+
+	var mu sync.Mutex
+	var keys, orderIDs []string
+
+	// The proxy lets the API create every order but drops the connection
+	// before the response of the first apply reaches the provider.
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/orders" {
+			proxy.ServeHTTP(w, r)
+			return
+		}
+
+		recorder := httptest.NewRecorder()
+		proxy.ServeHTTP(recorder, r)
+
+		var order struct {
+			ID int `json:"id"`
+		}
+		json.Unmarshal(recorder.Body.Bytes(), &order)
+
+		mu.Lock()
+		keys = append(keys, r.Header.Get(idempotencyKeyHeader))
+		orderIDs = append(orderIDs, strconv.Itoa(order.ID))
+		drop := len(keys) <= createOrderAttempts
+		mu.Unlock()
+
+		if drop {
+			conn, _, err := w.(http.Hijacker).Hijack()
+			if err != nil {
+				t.Errorf("unexpected error hijacking connection: %s", err)
+				return
+			}
+			conn.Close()
+			return
+		}
+
+		for key, values := range recorder.Header() {
+			w.Header()[key] = values
+		}
+		w.WriteHeader(recorder.Code)
+		w.Write(recorder.Body.Bytes())
+	}))
+	defer server.Close()
+
+	config := fmt.Sprintf(`
+provider "fsd" {
+  username = "education"
+  password = "test123"
+  host     = %q
+}
+
+resource "fsd_order" "test" {
+  items = [
+    {
+      coffee = {
+        id = 1
+      }
+      quantity = 1
+    },
+  ]
+}
+`, server.URL)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Every attempt of the first apply loses its response
+			{
+				Config:      config,
+				ExpectError: regexp.MustCompile(`Error creating order`),
+			},
+			// Re-applying resends the key and adopts the order created by
+			// the first attempt
+			{
+				Config: config,
+				Check: func(s *terraform.State) error {
+					mu.Lock()
+					defer mu.Unlock()
+
+					for _, key := range keys {
+						if key == "" || key != keys[0] {
+							return fmt.Errorf("expected every creation to send idempotency key %q, got %q", keys[0], keys)
+						}
+					}
+
+					return resource.ComposeAggregateTestCheckFunc(
+						resource.TestCheckResourceAttr("fsd_order.test", "id", orderIDs[0]),
+						resource.TestCheckResourceAttr("fsd_order.test", "idempotency_key", keys[0]),
+					)(s)
+				},
+			},
+		},
+	})
+}
+
+// Terraform plans a resource again at apply and fails if the plan changed,
+// so planning the same create twice must give the same result.
+func TestOrderResourcePlansCreateConsistently(t *testing.T) {
+	ctx := context.Background()
+
+	var schemaResp fwresource.SchemaResponse
+	(&orderResource{}).Schema(ctx, fwresource.SchemaRequest{}, &schemaResp)
+	objectType := schemaResp.Schema.Type().TerraformType(ctx).(tftypes.Object)
+
+	attributes := map[string]tftypes.Value{}
+	for name, attributeType := range objectType.AttributeTypes {
+		attributes[name] = tftypes.NewValue(attributeType, nil)
+	}
+	config, err := tfprotov6.NewDynamicValue(objectType, tftypes.NewValue(objectType, attributes))
+	if err != nil {
+		t.Fatalf("unexpected error building config: %s", err)
+	}
+	priorState, err := tfprotov6.NewDynamicValue(objectType, tftypes.NewValue(objectType, nil))
+	if err != nil {
+		t.Fatalf("unexpected error building prior state: %s", err)
+	}
+
+	server, err := providerserver.NewProtocol6WithError(New())()
+	if err != nil {
+		t.Fatalf("unexpected error creating provider server: %s", err)
+	}
+
+	plan := func(priorPrivate []byte) tftypes.Value {
+		resp, err := server.PlanResourceChange(ctx, &tfprotov6.PlanResourceChangeRequest{
+			TypeName:         "fsd_order",
+			PriorState:       &priorState,
+			ProposedNewState: &config,
+			Config:           &config,
+			PriorPrivate:     priorPrivate,
+		})
+		if err != nil {
+			t.Fatalf("unexpected error planning: %s", err)
+		}
+		for _, d := range resp.Diagnostics {
+			if d.Severity == tfprotov6.DiagnosticSeverityError {
+				t.Fatalf("unexpected error planning: %s: %s", d.Summary, d.Detail)
+			}
+		}
+
+		planned, err := resp.PlannedState.Unmarshal(objectType)
+		if err != nil {
+			t.Fatalf("unexpected error decoding plan: %s", err)
+		}
+		return planned
+	}
+
+	first, second := plan(nil), plan(nil)
+	if !first.Equal(second) {
+		t.Errorf("expected planning the same create twice to match, got:\n%s\n%s", first, second)
+	}
+
+	key, _, err := tftypes.WalkAttributePath(first, tftypes.NewAttributePath().WithAttributeName("idempotency_key"))
+	if err != nil {
+		t.Fatalf("unexpected error reading idempotency_key: %s", err)
+	}
+	if key.(tftypes.Value).IsKnown() {
+		t.Errorf("expected the idempotency key to be left to Create, got %s", key)
+	}
+
+	// The key of a creation that failed without a response is planned again.
+	private, err := json.Marshal(map[string][]byte{privateStateIdempotencyKey: []byte(`"key-1"`)})
+	if err != nil {
+		t.Fatalf("unexpected error building private state: %s", err)
+	}
+	key, _, err = tftypes.WalkAttributePath(plan(private), tftypes.NewAttributePath().WithAttributeName("idempotency_key"))
+	if err != nil {
+		t.Fatalf("unexpected error reading idempotency_key: %s", err)
+	}
+	if !key.(tftypes.Value).Equal(tftypes.NewValue(tftypes.String, "key-1")) {
+		t.Errorf("expected the key from private state to be planned, got %s", key)
+	}
+}
+
+func TestAccOrderResourceLabels(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+provider "fsd" {
+  username = "education"
+  password = "test123"
+  host     = "http://localhost:19090"
+
+  default_labels = {
+    team        = "platform"
+    cost-center = "1234"
+  }
+}
+
+resource "fsd_order" "test" {
+  labels = {
+    team = "payments"
+  }
+
+  items = [
+    {
+      coffee = {
+        id = 1
+      }
+      quantity = 1
+    },
+  ]
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("fsd_order.test", "labels.%", "1"),
+					resource.TestCheckResourceAttr("fsd_order.test", "labels_all.%", "2"),
+					resource.TestCheckResourceAttr("fsd_order.test", "labels_all.team", "payments"),
+					resource.TestCheckResourceAttr("fsd_order.test", "labels_all.cost-center", "1234"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccOrderResourceBudget(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+provider "fsd" {
+  username = "education"
+  password = "test123"
+  host     = "http://localhost:19090"
+
+  max_order_total = 500
+}
+
+resource "fsd_order" "test" {
+  items = [
+    {
+      coffee = {
+        id = 1
+      }
+      quantity = 5
+    },
+  ]
+}
+`,
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`costs 1000, which exceeds the max_order_total of 500`),
+			},
+		},
+	})
+}
+
+func TestAccOrderResourceExpectedPrice(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: providerConfig + `
+resource "fsd_order" "test" {
+  items = [
+    {
+      coffee = {
+        id = 1
+      }
+      quantity       = 1
+      expected_price = 150
+    },
+  ]
+}
+`,
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`Coffee 1 now costs 200 in the catalog, more than the expected_price of 150`),
+			},
+			{
+				Config: providerConfig + `
+resource "fsd_order" "test" {
+  items = [
+    {
+      coffee = {
+        id = 1
+      }
+      quantity       = 1
+      expected_price = 200
+    },
+  ]
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("fsd_order.test", "items.0.expected_price", "200"),
+					resource.TestCheckResourceAttr("fsd_order.test", "items.0.price_at_order", "200"),
+				),
+			},
+		},
+	})
+}
@@ -0,0 +1,157 @@
+package fsd
+
+import (
+	"context"
+	"fmt"
+
+	typs "github.com/gofsd/fsd-types"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/shopspring/decimal"
+)
+
+// keepItemSnapshots fills in the price_at_order and expected_price of items
+// read from the API. An item keeps the expected_price of the configured
+// item, and the price_at_order of the previous item, that holds the same
+// coffee, so reordering the items keeps their snapshots. Items of the same
+// coffee are matched in order. Items without a previous snapshot are
+// snapshotted at their current price.
+func keepItemSnapshots(items []orderItemModel, configured []orderItemModel, previous []orderItemModel) {
+	configuredUsed := make([]bool, len(configured))
+	previousUsed := make([]bool, len(previous))
+
+	for i := range items {
+		if j := matchItem(items[i], configured, configuredUsed); j >= 0 {
+			items[i].ExpectedPrice = configured[j].ExpectedPrice
+		}
+
+		if j := matchItem(items[i], previous, previousUsed); j >= 0 &&
+			!previous[j].PriceAtOrder.IsNull() && !previous[j].PriceAtOrder.IsUnknown() {
+			items[i].PriceAtOrder = previous[j].PriceAtOrder
+			continue
+		}
+
+		items[i].PriceAtOrder = items[i].Coffee.Price
+	}
+}
+
+// modifyPlanPriceSnapshots plans the price_at_order of the items of an
+// order update the way Update fills it in: an item keeps the snapshot of the
+// item in state that holds the same coffee, and only items new to the order
+// are left unknown until they are snapshotted at apply.
+func modifyPlanPriceSnapshots(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	items, diags := plannedOrderItems(ctx, req)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() || items == nil {
+		return
+	}
+
+	var previous []orderItemModel
+	diags = req.State.GetAttribute(ctx, path.Root("items"), &previous)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	previousUsed := make([]bool, len(previous))
+	for i, item := range items {
+		priceAtOrder := moneyUnknown()
+		if j := matchItem(item, previous, previousUsed); j >= 0 &&
+			!previous[j].PriceAtOrder.IsNull() && !previous[j].PriceAtOrder.IsUnknown() {
+			priceAtOrder = previous[j].PriceAtOrder
+		}
+
+		diags = resp.Plan.SetAttribute(ctx, path.Root("items").AtListIndex(i).AtName("price_at_order"), priceAtOrder)
+		resp.Diagnostics.Append(diags...)
+	}
+}
+
+// matchItem returns the index of the first candidate not used yet that
+// holds the same coffee as item and marks it used, or -1 if there is none.
+func matchItem(item orderItemModel, candidates []orderItemModel, used []bool) int {
+	for j, candidate := range candidates {
+		if !used[j] && candidate.Coffee.ID.Equal(item.Coffee.ID) {
+			used[j] = true
+			return j
+		}
+	}
+
+	return -1
+}
+
+// priceDriftWarnings reports items whose coffee price changed since the
+// order was placed.
+func priceDriftWarnings(items []orderItemModel) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	for i, item := range items {
+		priceAtOrder := item.PriceAtOrder.ValueBigFloat()
+		price := item.Coffee.Price.ValueBigFloat()
+		if priceAtOrder == nil || price == nil || formatMoney(priceAtOrder) == formatMoney(price) {
+			continue
+		}
+
+		diags.AddAttributeWarning(
+			path.Root("items").AtListIndex(i).AtName("coffee").AtName("price"),
+			"fsd Coffee Price Changed",
+			fmt.Sprintf("The price of coffee %d (%s) changed from %s when the order was placed to %s.",
+				item.Coffee.ID.ValueInt64(), item.Coffee.Name.ValueString(), formatMoney(priceAtOrder), formatMoney(price)),
+		)
+	}
+
+	return diags
+}
+
+// hasExpectedPrices reports whether any item sets an expected_price.
+func hasExpectedPrices(items []orderItemModel) bool {
+	for _, item := range items {
+		if !item.ExpectedPrice.IsNull() {
+			return true
+		}
+	}
+
+	return false
+}
+
+// checkExpectedPrices reports planned items whose coffee now costs more in
+// the catalog than their expected_price.
+func checkExpectedPrices(items []orderItemModel, coffees []typs.Coffee) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	prices := make(map[int64]float64, len(coffees))
+	for _, coffee := range coffees {
+		prices[int64(coffee.ID)] = coffee.Price
+	}
+
+	for i, item := range items {
+		if item.ExpectedPrice.IsNull() || item.ExpectedPrice.IsUnknown() || item.Coffee.ID.IsNull() || item.Coffee.ID.IsUnknown() {
+			continue
+		}
+
+		price, ok := prices[item.Coffee.ID.ValueInt64()]
+		if !ok {
+			continue
+		}
+
+		expected, err := decimalFromNumber(item.ExpectedPrice.ValueBigFloat())
+		if err != nil {
+			continue
+		}
+
+		current := decimal.NewFromFloat(price)
+		if current.LessThanOrEqual(expected) {
+			continue
+		}
+
+		diags.AddAttributeError(
+			path.Root("items").AtListIndex(i).AtName("expected_price"),
+			"fsd Coffee Price Exceeds Expected Price",
+			fmt.Sprintf("Coffee %d now costs %s in the catalog, more than the expected_price of %s. "+
+				"Review the new price and raise expected_price to accept it.",
+				item.Coffee.ID.ValueInt64(), current, expected),
+		)
+	}
+
+	return diags
+}
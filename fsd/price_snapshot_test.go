@@ -0,0 +1,169 @@
+package fsd
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	typs "github.com/gofsd/fsd-types"
+	"github.com/hashicorp/terraform-plugin-framework-jsontypes/jsontypes"
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	fwresource "github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+func TestKeepItemSnapshots(t *testing.T) {
+	items := []orderItemModel{
+		{Coffee: orderItemCoffeeModel{ID: types.Int64Value(1), Price: moneyFromFloat64(250)}, ExpectedPrice: moneyNull()},
+		{Coffee: orderItemCoffeeModel{ID: types.Int64Value(3), Price: moneyFromFloat64(150)}, ExpectedPrice: moneyNull()},
+	}
+	configured := []orderItemModel{
+		{Coffee: orderItemCoffeeModel{ID: types.Int64Value(1)}, ExpectedPrice: moneyFromFloat64(300)},
+		{Coffee: orderItemCoffeeModel{ID: types.Int64Value(3)}, ExpectedPrice: moneyNull()},
+	}
+	previous := []orderItemModel{
+		{Coffee: orderItemCoffeeModel{ID: types.Int64Value(1)}, PriceAtOrder: moneyFromFloat64(200)},
+		{Coffee: orderItemCoffeeModel{ID: types.Int64Value(2)}, PriceAtOrder: moneyFromFloat64(100)},
+	}
+
+	keepItemSnapshots(items, configured, previous)
+
+	if got := formatMoney(items[0].PriceAtOrder.ValueBigFloat()); got != "200" {
+		t.Errorf("expected the snapshot of an unchanged item to be kept, got %s", got)
+	}
+	if got := formatMoney(items[0].ExpectedPrice.ValueBigFloat()); got != "300" {
+		t.Errorf("expected the configured expected_price, got %s", got)
+	}
+	if got := formatMoney(items[1].PriceAtOrder.ValueBigFloat()); got != "150" {
+		t.Errorf("expected a replaced item to be snapshotted at its current price, got %s", got)
+	}
+	if !items[1].ExpectedPrice.IsNull() {
+		t.Errorf("expected a null expected_price, got %s", items[1].ExpectedPrice)
+	}
+
+	diags := priceDriftWarnings(items)
+	if diags.WarningsCount() != 1 || !strings.Contains(diags.Warnings()[0].Detail(), "changed from 200 when the order was placed to 250") {
+		t.Errorf("expected one price drift warning, got %v", diags)
+	}
+}
+
+func TestKeepItemSnapshotsReorderedItems(t *testing.T) {
+	items := []orderItemModel{
+		{Coffee: orderItemCoffeeModel{ID: types.Int64Value(3), Price: moneyFromFloat64(150)}, ExpectedPrice: moneyNull()},
+		{Coffee: orderItemCoffeeModel{ID: types.Int64Value(1), Price: moneyFromFloat64(250)}, ExpectedPrice: moneyNull()},
+		{Coffee: orderItemCoffeeModel{ID: types.Int64Value(1), Price: moneyFromFloat64(250)}, ExpectedPrice: moneyNull()},
+	}
+	configured := []orderItemModel{
+		{Coffee: orderItemCoffeeModel{ID: types.Int64Value(3)}, ExpectedPrice: moneyFromFloat64(160)},
+		{Coffee: orderItemCoffeeModel{ID: types.Int64Value(1)}, ExpectedPrice: moneyFromFloat64(300)},
+		{Coffee: orderItemCoffeeModel{ID: types.Int64Value(1)}, ExpectedPrice: moneyFromFloat64(310)},
+	}
+	previous := []orderItemModel{
+		{Coffee: orderItemCoffeeModel{ID: types.Int64Value(1)}, PriceAtOrder: moneyFromFloat64(200)},
+		{Coffee: orderItemCoffeeModel{ID: types.Int64Value(3)}, PriceAtOrder: moneyFromFloat64(120)},
+	}
+
+	keepItemSnapshots(items, configured, previous)
+
+	for i, expected := range []struct{ priceAtOrder, expectedPrice string }{
+		{"120", "160"},
+		{"200", "300"},
+		// The second item of coffee 1 was added by this change.
+		{"250", "310"},
+	} {
+		if got := formatMoney(items[i].PriceAtOrder.ValueBigFloat()); got != expected.priceAtOrder {
+			t.Errorf("item %d: expected price_at_order %s, got %s", i, expected.priceAtOrder, got)
+		}
+		if got := formatMoney(items[i].ExpectedPrice.ValueBigFloat()); got != expected.expectedPrice {
+			t.Errorf("item %d: expected expected_price %s, got %s", i, expected.expectedPrice, got)
+		}
+	}
+}
+
+func TestModifyPlanPriceSnapshots(t *testing.T) {
+	ctx := context.Background()
+
+	var schemaResp fwresource.SchemaResponse
+	(&orderResource{}).Schema(ctx, fwresource.SchemaRequest{}, &schemaResp)
+	objectType := schemaResp.Schema.Type().TerraformType(ctx).(tftypes.Object)
+
+	order := func(items ...orderItemModel) orderResourceModel {
+		return orderResourceModel{
+			Items:     items,
+			Labels:    types.MapNull(types.StringType),
+			LabelsAll: types.MapNull(types.StringType),
+			RawJSON:   jsontypes.NewNormalizedNull(),
+			Timeouts: timeouts.Value{Object: types.ObjectNull(map[string]attr.Type{
+				"create": types.StringType,
+				"update": types.StringType,
+			})},
+		}
+	}
+	item := func(coffeeID int64, priceAtOrder moneyValue) orderItemModel {
+		return orderItemModel{
+			Coffee:        orderItemCoffeeModel{ID: types.Int64Value(coffeeID), Price: moneyNull(), PriceConverted: moneyNull()},
+			Quantity:      types.Int64Value(1),
+			PriceAtOrder:  priceAtOrder,
+			ExpectedPrice: moneyNull(),
+		}
+	}
+
+	state := tfsdk.State{Schema: schemaResp.Schema, Raw: tftypes.NewValue(objectType, nil)}
+	if diags := state.Set(ctx, order(item(1, moneyFromFloat64(200)), item(3, moneyFromFloat64(120)))); diags.HasError() {
+		t.Fatalf("unexpected error building state: %v", diags)
+	}
+
+	// The items are reordered and coffee 2 is added. Terraform proposes
+	// snapshots by list index, or none at all.
+	plan := tfsdk.Plan{Schema: schemaResp.Schema, Raw: tftypes.NewValue(objectType, nil)}
+	if diags := plan.Set(ctx, order(item(3, moneyFromFloat64(200)), item(2, moneyUnknown()), item(1, moneyUnknown()))); diags.HasError() {
+		t.Fatalf("unexpected error building plan: %v", diags)
+	}
+
+	resp := fwresource.ModifyPlanResponse{Plan: plan}
+	modifyPlanPriceSnapshots(ctx, fwresource.ModifyPlanRequest{State: state, Plan: plan}, &resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected error: %v", resp.Diagnostics)
+	}
+
+	var planned orderResourceModel
+	if diags := resp.Plan.Get(ctx, &planned); diags.HasError() {
+		t.Fatalf("unexpected error reading plan: %v", diags)
+	}
+
+	if got := formatMoney(planned.Items[0].PriceAtOrder.ValueBigFloat()); got != "120" {
+		t.Errorf("expected coffee 3 to keep its snapshot, got %s", got)
+	}
+	if !planned.Items[1].PriceAtOrder.IsUnknown() {
+		t.Errorf("expected the added item to be snapshotted at apply, got %s", planned.Items[1].PriceAtOrder)
+	}
+	if got := formatMoney(planned.Items[2].PriceAtOrder.ValueBigFloat()); got != "200" {
+		t.Errorf("expected coffee 1 to keep its snapshot, got %s", got)
+	}
+}
+
+func TestCheckExpectedPrices(t *testing.T) {
+	coffees := []typs.Coffee{
+		{ID: 1, Price: 200},
+		{ID: 2, Price: 3.3},
+	}
+	items := []orderItemModel{
+		{Coffee: orderItemCoffeeModel{ID: types.Int64Value(1)}, ExpectedPrice: moneyFromFloat64(200)},
+		{Coffee: orderItemCoffeeModel{ID: types.Int64Value(2)}, ExpectedPrice: moneyFromFloat64(3.25)},
+		{Coffee: orderItemCoffeeModel{ID: types.Int64Value(2)}, ExpectedPrice: moneyNull()},
+		{Coffee: orderItemCoffeeModel{ID: types.Int64Unknown()}, ExpectedPrice: moneyFromFloat64(1)},
+		{Coffee: orderItemCoffeeModel{ID: types.Int64Value(9)}, ExpectedPrice: moneyFromFloat64(1)},
+	}
+
+	if !hasExpectedPrices(items) {
+		t.Fatal("expected items to have expected prices")
+	}
+
+	diags := checkExpectedPrices(items, coffees)
+	if diags.ErrorsCount() != 1 || !strings.Contains(diags.Errors()[0].Detail(), "Coffee 2 now costs 3.3 in the catalog, more than the expected_price of 3.25") {
+		t.Errorf("expected one expected_price error, got %v", diags)
+	}
+}
@@ -0,0 +1,78 @@
+package fsd
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+)
+
+// privateStateIdempotencyKey holds the idempotency key an order is created
+// with, from the plan until the order exists.
+const privateStateIdempotencyKey = "idempotency_key"
+
+// privateStateETag holds the ETag of the API object as last read, so that
+// changes are only applied if nobody else modified it in the meantime.
+const privateStateETag = "etag"
+
+// privateState is the private state data of a framework request or
+// response. Values must be valid JSON.
+type privateState interface {
+	GetKey(ctx context.Context, key string) ([]byte, diag.Diagnostics)
+	SetKey(ctx context.Context, key string, value []byte) diag.Diagnostics
+}
+
+// getPrivateString returns a string value from private state, or "" if the
+// key is not set.
+func getPrivateString(ctx context.Context, private privateState, key string) (string, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	raw, getDiags := private.GetKey(ctx, key)
+	diags.Append(getDiags...)
+	if len(raw) == 0 {
+		return "", diags
+	}
+
+	var value string
+	if err := json.Unmarshal(raw, &value); err != nil {
+		diags.AddError(
+			"Invalid Private State",
+			"Could not decode the "+key+" value of the resource private state: "+err.Error(),
+		)
+		return "", diags
+	}
+
+	return value, diags
+}
+
+// setPrivateString stores a string value in private state. An empty value
+// removes the key.
+func setPrivateString(ctx context.Context, private privateState, key string, value string) diag.Diagnostics {
+	if value == "" {
+		return private.SetKey(ctx, key, nil)
+	}
+
+	raw, err := json.Marshal(value)
+	if err != nil {
+		var diags diag.Diagnostics
+		diags.AddError(
+			"Invalid Private State",
+			"Could not encode the "+key+" value of the resource private state: "+err.Error(),
+		)
+		return diags
+	}
+
+	return private.SetKey(ctx, key, raw)
+}
+
+// newIdempotencyKey returns a random key for a new API object.
+func newIdempotencyKey() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(b), nil
+}
@@ -2,16 +2,23 @@ package fsd
 
 import (
 	"context"
+	"net/http"
 	"os"
+	"regexp"
+	"strings"
+	"time"
 
 	typs "github.com/gofsd/fsd-types"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/provider"
 	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/shopspring/decimal"
 )
 
 // Ensure the implementation satisfies the expected interfaces
@@ -27,11 +34,36 @@ func New() provider.Provider {
 // fsdProvider is the provider implementation.
 type fsdProvider struct{}
 
+// fsdProviderData is passed to data sources and resources as ProviderData.
+type fsdProviderData struct {
+	client        *fsdClient
+	catalog       *catalogCache
+	defaultLabels map[string]string
+	converter     *currencyConverter
+	budget        orderBudget
+	coffeePolicy  coffeePolicy
+}
+
 // fsdProviderModel maps provider schema data to a Go type.
 type fsdProviderModel struct {
-	Host     types.String `tfsdk:"host"`
-	Username types.String `tfsdk:"username"`
-	Password types.String `tfsdk:"password"`
+	Host                  types.String  `tfsdk:"host"`
+	Username              types.String  `tfsdk:"username"`
+	Password              types.String  `tfsdk:"password"`
+	TokenCachePath        types.String  `tfsdk:"token_cache_path"`
+	RequestsPerSecond     types.Float64 `tfsdk:"requests_per_second"`
+	MaxConcurrentRequests types.Int64   `tfsdk:"max_concurrent_requests"`
+	CatalogCacheTTL       types.String  `tfsdk:"catalog_cache_ttl"`
+	DefaultLabels         types.Map     `tfsdk:"default_labels"`
+	Currency              types.String  `tfsdk:"currency"`
+	ExchangeRates         types.Map     `tfsdk:"exchange_rates"`
+	ExchangeRatesFile     types.String  `tfsdk:"exchange_rates_file"`
+	MaxOrderTotal         types.Number  `tfsdk:"max_order_total"`
+	MaxItemQuantity       types.Int64   `tfsdk:"max_item_quantity"`
+	BudgetEnforcement     types.String  `tfsdk:"budget_enforcement"`
+	AllowedCoffeeIDs      types.Set     `tfsdk:"allowed_coffee_ids"`
+	DeniedCoffeeIDs       types.Set     `tfsdk:"denied_coffee_ids"`
+	AllowedCoffeeNames    types.List    `tfsdk:"allowed_coffee_name_patterns"`
+	DeniedCoffeeNames     types.List    `tfsdk:"denied_coffee_name_patterns"`
 }
 
 // Metadata returns the provider type name.
@@ -57,6 +89,82 @@ func (p *fsdProvider) Schema(_ context.Context, _ provider.SchemaRequest, resp *
 				Optional:    true,
 				Sensitive:   true,
 			},
+			"token_cache_path": schema.StringAttribute{
+				Description: "Path of a file used to cache API tokens between provider runs, keyed by host and username. " +
+					"Caching is disabled unless set. May also be provided via fsd_TOKEN_CACHE_PATH environment variable.",
+				Optional: true,
+			},
+			"requests_per_second": schema.Float64Attribute{
+				Description: "Maximum number of fsd API requests started per second, shared by all resources and data sources. " +
+					"Requests are not rate limited unless set.",
+				Optional: true,
+			},
+			"max_concurrent_requests": schema.Int64Attribute{
+				Description: "Maximum number of fsd API requests in flight at the same time. Concurrency is not limited unless set.",
+				Optional:    true,
+			},
+			"catalog_cache_ttl": schema.StringAttribute{
+				Description: "How long the coffee catalog is reused by data sources and resources, as a duration such as \"30s\" or \"5m\". " +
+					"Defaults to 5m. Set to \"0s\" to fetch the catalog on every lookup.",
+				Optional: true,
+			},
+			"default_labels": schema.MapAttribute{
+				Description: "Labels added to every resource that supports labels. Labels set on a resource take precedence.",
+				ElementType: types.StringType,
+				Optional:    true,
+			},
+			"currency": schema.StringAttribute{
+				Description: "Currency code, such as \"EUR\", that prices are converted to in price_converted attributes. " +
+					"Requires an exchange rate. Prices are only reported in the fsd API currency unless set.",
+				Optional: true,
+			},
+			"exchange_rates": schema.MapAttribute{
+				Description: "Units of each currency per unit of the fsd API currency, keyed by currency code. " +
+					"Takes precedence over exchange_rates_file.",
+				ElementType: types.NumberType,
+				Optional:    true,
+			},
+			"exchange_rates_file": schema.StringAttribute{
+				Description: "Path to a JSON file mapping currency codes to exchange rates, in the same form as exchange_rates.",
+				Optional:    true,
+			},
+			"max_order_total": schema.NumberAttribute{
+				Description: "Highest total, in the provider currency, that an fsd_order may cost. " +
+					"Checked at plan time using catalog prices. Orders may override it with their budget.",
+				Optional: true,
+			},
+			"max_item_quantity": schema.Int64Attribute{
+				Description: "Highest quantity of a single fsd_order item. Orders may override it with their budget.",
+				Optional:    true,
+			},
+			"budget_enforcement": schema.StringAttribute{
+				Description: "How budget limits are enforced: \"strict\" fails the plan, \"advisory\" only warns. Defaults to \"strict\".",
+				Optional:    true,
+				Validators: []validator.String{
+					stringOneOf(budgetEnforcementStrict, budgetEnforcementAdvisory),
+				},
+			},
+			"allowed_coffee_ids": schema.SetAttribute{
+				Description: "Coffee ids that fsd_order and fsd_try items may use. All coffees are allowed unless set.",
+				ElementType: types.Int64Type,
+				Optional:    true,
+			},
+			"denied_coffee_ids": schema.SetAttribute{
+				Description: "Coffee ids that fsd_order and fsd_try items may not use.",
+				ElementType: types.Int64Type,
+				Optional:    true,
+			},
+			"allowed_coffee_name_patterns": schema.ListAttribute{
+				Description: "Regular expressions of which a coffee name must match one for fsd_order and fsd_try items to use it, " +
+					"such as \"^HCP \". All coffees are allowed unless set.",
+				ElementType: types.StringType,
+				Optional:    true,
+			},
+			"denied_coffee_name_patterns": schema.ListAttribute{
+				Description: "Regular expressions of coffee names that fsd_order and fsd_try items may not use, such as \"(?i)decaf\".",
+				ElementType: types.StringType,
+				Optional:    true,
+			},
 		},
 	}
 }
@@ -126,6 +234,40 @@ func (p *fsdProvider) Configure(ctx context.Context, req provider.ConfigureReque
 		password = config.Password.ValueString()
 	}
 
+	tokenCachePath := os.Getenv("fsd_TOKEN_CACHE_PATH")
+	if !config.TokenCachePath.IsNull() {
+		tokenCachePath = config.TokenCachePath.ValueString()
+	}
+
+	if !config.RequestsPerSecond.IsNull() && !config.RequestsPerSecond.IsUnknown() && config.RequestsPerSecond.ValueFloat64() <= 0 {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("requests_per_second"),
+			"Invalid fsd API Request Rate",
+			"The requests_per_second value must be greater than zero. Remove it to disable rate limiting.",
+		)
+	}
+
+	if !config.MaxConcurrentRequests.IsNull() && !config.MaxConcurrentRequests.IsUnknown() && config.MaxConcurrentRequests.ValueInt64() < 1 {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("max_concurrent_requests"),
+			"Invalid fsd API Concurrency Limit",
+			"The max_concurrent_requests value must be at least 1. Remove it to disable the concurrency limit.",
+		)
+	}
+
+	catalogCacheTTL := catalogCacheDefaultTTL
+	if !config.CatalogCacheTTL.IsNull() && !config.CatalogCacheTTL.IsUnknown() {
+		ttl, err := time.ParseDuration(config.CatalogCacheTTL.ValueString())
+		if err != nil || ttl < 0 {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("catalog_cache_ttl"),
+				"Invalid fsd Catalog Cache TTL",
+				"The catalog_cache_ttl value must be a non-negative duration such as \"30s\" or \"5m\", got: "+config.CatalogCacheTTL.ValueString(),
+			)
+		}
+		catalogCacheTTL = ttl
+	}
+
 	// If any of the expected configurations are missing, return
 	// errors with provider-specific guidance.
 
@@ -159,40 +301,247 @@ func (p *fsdProvider) Configure(ctx context.Context, req provider.ConfigureReque
 		)
 	}
 
+	var defaultLabels map[string]string
+	if config.DefaultLabels.IsUnknown() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("default_labels"),
+			"Unknown fsd Default Labels",
+			"The provider cannot apply default labels as there is an unknown configuration value for default_labels. "+
+				"Either target apply the source of the value first or set the value statically in the configuration.",
+		)
+	} else if !config.DefaultLabels.IsNull() {
+		diags = config.DefaultLabels.ElementsAs(ctx, &defaultLabels, false)
+		resp.Diagnostics.Append(diags...)
+	}
+
+	var converter *currencyConverter
+	if config.Currency.IsUnknown() || config.ExchangeRates.IsUnknown() || config.ExchangeRatesFile.IsUnknown() {
+		resp.Diagnostics.AddError(
+			"Unknown fsd Currency Configuration",
+			"The provider cannot convert prices as there is an unknown configuration value for currency, exchange_rates or exchange_rates_file. "+
+				"Either target apply the source of the value first or set the values statically in the configuration.",
+		)
+	} else if config.Currency.ValueString() != "" {
+		rates := map[string]decimal.Decimal{}
+
+		if !config.ExchangeRatesFile.IsNull() {
+			fileRates, err := loadExchangeRatesFile(config.ExchangeRatesFile.ValueString())
+			if err != nil {
+				resp.Diagnostics.AddAttributeError(
+					path.Root("exchange_rates_file"),
+					"Invalid fsd Exchange Rates File",
+					"The exchange rates file could not be read: "+err.Error(),
+				)
+			}
+			for currency, rate := range fileRates {
+				rates[currency] = rate
+			}
+		}
+
+		var configRates map[string]types.Number
+		if !config.ExchangeRates.IsNull() {
+			diags = config.ExchangeRates.ElementsAs(ctx, &configRates, false)
+			resp.Diagnostics.Append(diags...)
+		}
+		for currency, rate := range configRates {
+			value, err := decimalFromNumber(rate.ValueBigFloat())
+			if err != nil {
+				resp.Diagnostics.AddAttributeError(
+					path.Root("exchange_rates").AtMapKey(currency),
+					"Invalid fsd Exchange Rate",
+					"The exchange rate for "+currency+" is not a valid decimal: "+err.Error(),
+				)
+				continue
+			}
+			rates[strings.ToUpper(currency)] = value
+		}
+
+		if !resp.Diagnostics.HasError() {
+			var err error
+			converter, err = newCurrencyConverter(config.Currency.ValueString(), rates)
+			if err != nil {
+				resp.Diagnostics.AddAttributeError(
+					path.Root("currency"),
+					"Invalid fsd Currency",
+					"Prices cannot be converted to the configured currency: "+err.Error()+". "+
+						"Add a rate for it to exchange_rates or exchange_rates_file.",
+				)
+			}
+		}
+	}
+
+	budget, err := orderBudget{}.withOverrides(&orderBudgetModel{
+		MaxOrderTotal:   config.MaxOrderTotal,
+		MaxItemQuantity: config.MaxItemQuantity,
+		Enforcement:     config.BudgetEnforcement,
+	})
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("max_order_total"),
+			"Invalid fsd Budget",
+			"The max_order_total value is not a valid amount: "+err.Error(),
+		)
+	}
+	if budget.maxOrderTotal.Valid && !budget.maxOrderTotal.Decimal.IsPositive() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("max_order_total"),
+			"Invalid fsd Budget",
+			"The max_order_total value must be greater than zero. Remove it to disable the limit.",
+		)
+	}
+	if !config.MaxItemQuantity.IsNull() && !config.MaxItemQuantity.IsUnknown() && budget.maxItemQuantity < 1 {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("max_item_quantity"),
+			"Invalid fsd Budget",
+			"The max_item_quantity value must be at least 1. Remove it to disable the limit.",
+		)
+	}
+
+	var policy coffeePolicy
+	policy.allowedIDs = coffeeIDSet(ctx, path.Root("allowed_coffee_ids"), config.AllowedCoffeeIDs, &resp.Diagnostics)
+	policy.deniedIDs = coffeeIDSet(ctx, path.Root("denied_coffee_ids"), config.DeniedCoffeeIDs, &resp.Diagnostics)
+	policy.allowedNames = coffeeNamePatterns(ctx, path.Root("allowed_coffee_name_patterns"), config.AllowedCoffeeNames, &resp.Diagnostics)
+	policy.deniedNames = coffeeNamePatterns(ctx, path.Root("denied_coffee_name_patterns"), config.DeniedCoffeeNames, &resp.Diagnostics)
+
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	ctx = tflog.SetField(ctx, "fsd_host", host)
-	ctx = tflog.SetField(ctx, "fsd_username", username)
-	ctx = tflog.SetField(ctx, "fsd_password", password)
-	ctx = tflog.MaskFieldValuesWithFieldKeys(ctx, "fsd_password")
+	logging := httpLogging{
+		fields: map[string]any{
+			"fsd_host":     host,
+			"fsd_username": username,
+		},
+		secrets:     []string{password},
+		traceBodies: httpTraceEnabled(),
+	}
+	ctx = logging.context(ctx)
 
 	tflog.Debug(ctx, "Creating fsd client")
 
-	// Create a new fsd client using the configuration values
-	client, err := typs.NewClient(&host, &username, &password)
+	// Create a new fsd client using the configuration values. Sign-in is
+	// done by the provider so that it honors ctx, is logged like every other
+	// API call and can be skipped when a cached token is still valid. The
+	// client is built directly as typs.NewClient ignores the host.
+	client := &typs.Client{
+		HostURL: host,
+		HTTPClient: &http.Client{
+			Timeout: clientTimeout,
+			Transport: newThrottlingTransport(
+				logging,
+				newLoggingTransport(logging, nil),
+				config.RequestsPerSecond.ValueFloat64(),
+				config.MaxConcurrentRequests.ValueInt64(),
+			),
+		},
+		Auth: typs.AuthStruct{
+			Username: username,
+			Password: password,
+		},
+	}
+
+	providerClient := &fsdClient{Client: client}
+	if tokenCachePath != "" {
+		providerClient.tokenCache = newTokenCache(tokenCachePath)
+	}
+
+	err = providerClient.authenticate(ctx)
 	if err != nil {
-		resp.Diagnostics.AddError(
+		addAPIErrorDiagnostics(
+			&resp.Diagnostics,
 			"Unable to Create fsd API Client",
-			"An unexpected error occurred when creating the fsd API client. "+
-				"If the error is not clear, please contact the provider developers.\n\n"+
-				"fsd Client Error: "+err.Error(),
+			"An unexpected error occurred when signing in to the fsd API",
+			err,
 		)
 		return
 	}
+	ctx = maskSecrets(ctx, client.Token)
 
 	// Make the fsd client available during DataSource and Resource
 	// type Configure methods.
-	resp.DataSourceData = client
-	resp.ResourceData = client
+	providerData := &fsdProviderData{
+		client:        providerClient,
+		catalog:       newCatalogCache(providerClient, catalogCacheTTL),
+		defaultLabels: defaultLabels,
+		converter:     converter,
+		budget:        budget,
+		coffeePolicy:  policy,
+	}
+	resp.DataSourceData = providerData
+	resp.ResourceData = providerData
 	tflog.Info(ctx, "Configured fsd client", map[string]any{"success": true})
 }
 
+// coffeeIDSet returns the coffee ids of a provider allow or deny list, or
+// nil if the list is not set.
+func coffeeIDSet(ctx context.Context, attributePath path.Path, ids types.Set, diags *diag.Diagnostics) map[int64]bool {
+	if ids.IsNull() {
+		return nil
+	}
+	if ids.IsUnknown() {
+		addUnknownCoffeeListError(diags, attributePath)
+		return nil
+	}
+
+	var values []int64
+	diags.Append(ids.ElementsAs(ctx, &values, false)...)
+
+	set := make(map[int64]bool, len(values))
+	for _, id := range values {
+		set[id] = true
+	}
+
+	return set
+}
+
+// coffeeNamePatterns compiles the regular expressions of a provider allow or
+// deny list.
+func coffeeNamePatterns(ctx context.Context, attributePath path.Path, patterns types.List, diags *diag.Diagnostics) []*regexp.Regexp {
+	if patterns.IsNull() {
+		return nil
+	}
+	if patterns.IsUnknown() {
+		addUnknownCoffeeListError(diags, attributePath)
+		return nil
+	}
+
+	var values []string
+	diags.Append(patterns.ElementsAs(ctx, &values, false)...)
+
+	compiled := make([]*regexp.Regexp, 0, len(values))
+	for i, value := range values {
+		pattern, err := regexp.Compile(value)
+		if err != nil {
+			diags.AddAttributeError(
+				attributePath.AtListIndex(i),
+				"Invalid Coffee Name Pattern",
+				"The value is not a valid regular expression: "+err.Error(),
+			)
+			continue
+		}
+		compiled = append(compiled, pattern)
+	}
+
+	return compiled
+}
+
+// addUnknownCoffeeListError reports an allow or deny list that is not known
+// when the provider is configured.
+func addUnknownCoffeeListError(diags *diag.Diagnostics, attributePath path.Path) {
+	diags.AddAttributeError(
+		attributePath,
+		"Unknown fsd Coffee List",
+		"The provider cannot restrict coffees as there is an unknown configuration value for "+attributePath.String()+". "+
+			"Either target apply the source of the value first or set the value statically in the configuration.",
+	)
+}
+
 // DataSources defines the data sources implemented in the provider.
 func (p *fsdProvider) DataSources(_ context.Context) []func() datasource.DataSource {
 	return []func() datasource.DataSource{
+		NewAPIRequestDataSource,
 		NewCoffeesDataSource,
+		NewOrderDataSource,
 		NewTryDataSource,
 	}
 }
@@ -200,7 +549,10 @@ func (p *fsdProvider) DataSources(_ context.Context) []func() datasource.DataSou
 // Resources defines the resources implemented in the provider.
 func (p *fsdProvider) Resources(_ context.Context) []func() resource.Resource {
 	return []func() resource.Resource{
+		NewAPIObjectResource,
 		NewOrderResource,
+		NewOrderItemResource,
 		NewTryResource,
+		NewUserResource,
 	}
 }
@@ -1,8 +1,17 @@
 package fsd
 
 import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/provider"
 	"github.com/hashicorp/terraform-plugin-framework/providerserver"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
 	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
 )
 
 const (
@@ -28,3 +37,61 @@ var (
 		"fsd": providerserver.NewProtocol6WithError(New()),
 	}
 )
+
+func TestProviderConfigure(t *testing.T) {
+	t.Setenv("fsd_TOKEN_CACHE_PATH", "")
+
+	var signIns atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/signin" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		signIns.Add(1)
+		w.Write([]byte(`{"user_id":1,"username":"education","token":"issued-token"}`))
+	}))
+	defer server.Close()
+
+	ctx := context.Background()
+	p := New()
+
+	var schemaResp provider.SchemaResponse
+	p.Schema(ctx, provider.SchemaRequest{}, &schemaResp)
+
+	configType := schemaResp.Schema.Type().TerraformType(ctx).(tftypes.Object)
+	configValues := map[string]tftypes.Value{}
+	for name, attributeType := range configType.AttributeTypes {
+		configValues[name] = tftypes.NewValue(attributeType, nil)
+	}
+	configValues["host"] = tftypes.NewValue(tftypes.String, server.URL)
+	configValues["username"] = tftypes.NewValue(tftypes.String, "education")
+	configValues["password"] = tftypes.NewValue(tftypes.String, "test123")
+
+	req := provider.ConfigureRequest{
+		Config: tfsdk.Config{
+			Raw:    tftypes.NewValue(configType, configValues),
+			Schema: schemaResp.Schema,
+		},
+	}
+	var resp provider.ConfigureResponse
+	p.Configure(ctx, req, &resp)
+
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected error configuring the provider: %v", resp.Diagnostics)
+	}
+	if got := signIns.Load(); got != 1 {
+		t.Errorf("expected the provider to sign in at the configured host once, got %d sign-ins", got)
+	}
+
+	providerData, ok := resp.ResourceData.(*fsdProviderData)
+	if !ok {
+		t.Fatalf("expected provider data, got %T", resp.ResourceData)
+	}
+	if got := providerData.client.HostURL; got != server.URL {
+		t.Errorf("expected the client to use host %s, got %s", server.URL, got)
+	}
+	if got := providerData.client.currentToken(); got != "issued-token" {
+		t.Errorf("expected the issued token, got %q", got)
+	}
+}
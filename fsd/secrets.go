@@ -0,0 +1,113 @@
+package fsd
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// redactedValue replaces secret values in log output and diagnostics.
+const redactedValue = "***REDACTED***"
+
+// secretFieldKeys are log field and JSON body keys whose values are always
+// masked, whatever their content.
+var secretFieldKeys = []string{
+	"fsd_password",
+	"fsd_token",
+	"password",
+	"token",
+	"authorization",
+	"Authorization",
+}
+
+// secretHeaders are HTTP headers whose values are redacted before logging.
+var secretHeaders = []string{
+	"Authorization",
+	"Cookie",
+	"Set-Cookie",
+}
+
+// maskSecrets returns a context whose root and fsd_http loggers mask every
+// known secret key and the given secret values wherever they appear in
+// messages or fields.
+func maskSecrets(ctx context.Context, secrets ...string) context.Context {
+	ctx = tflog.MaskFieldValuesWithFieldKeys(ctx, secretFieldKeys...)
+	ctx = tflog.SubsystemMaskFieldValuesWithFieldKeys(ctx, httpLogSubsystem, secretFieldKeys...)
+
+	var values []string
+	for _, secret := range secrets {
+		if secret != "" {
+			values = append(values, secret)
+		}
+	}
+	if len(values) > 0 {
+		ctx = tflog.MaskLogStrings(ctx, values...)
+		ctx = tflog.SubsystemMaskLogStrings(ctx, httpLogSubsystem, values...)
+	}
+
+	return ctx
+}
+
+// redactHeaders returns a copy of the headers that is safe to log.
+func redactHeaders(header http.Header) map[string]string {
+	redacted := make(map[string]string, len(header))
+	for key, values := range header {
+		redacted[key] = strings.Join(values, ", ")
+	}
+
+	for _, key := range secretHeaders {
+		if _, ok := redacted[http.CanonicalHeaderKey(key)]; ok {
+			redacted[http.CanonicalHeaderKey(key)] = redactedValue
+		}
+	}
+
+	return redacted
+}
+
+// redactBody returns the body with the values of secret JSON keys replaced.
+// Bodies that are not JSON are returned unchanged.
+func redactBody(body []byte) string {
+	var decoded any
+	if err := json.Unmarshal(body, &decoded); err != nil {
+		return string(body)
+	}
+
+	redacted, err := json.Marshal(redactJSON(decoded))
+	if err != nil {
+		return string(body)
+	}
+
+	return string(redacted)
+}
+
+func redactJSON(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		for key, nested := range v {
+			if isSecretKey(key) {
+				v[key] = redactedValue
+				continue
+			}
+			v[key] = redactJSON(nested)
+		}
+	case []any:
+		for i, nested := range v {
+			v[i] = redactJSON(nested)
+		}
+	}
+
+	return value
+}
+
+func isSecretKey(key string) bool {
+	for _, secret := range secretFieldKeys {
+		if strings.EqualFold(key, secret) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,90 @@
+package fsd
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-log/tflogtest"
+)
+
+func TestMaskSecretsFields(t *testing.T) {
+	var output bytes.Buffer
+
+	ctx := tflogtest.RootLogger(context.Background(), &output)
+	ctx = maskSecrets(ctx, "s3cret-password")
+
+	tflog.Debug(ctx, "configuring with s3cret-password", map[string]any{
+		"fsd_password": "s3cret-password",
+		"fsd_token":    "issued-token",
+		"note":         "password is s3cret-password",
+	})
+
+	assertNoSecrets(t, output.String(), "s3cret-password", "issued-token")
+}
+
+func TestLoggingTransportRedactsSecrets(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Set-Cookie", "session=cookie-secret")
+		w.Write([]byte(`{"user_id":1,"username":"education","token":"issued-token"}`))
+	}))
+	defer server.Close()
+
+	var output bytes.Buffer
+
+	ctx := tflogtest.RootLogger(context.Background(), &output)
+
+	logging := httpLogging{secrets: []string{"s3cret-password"}, traceBodies: true}
+	client := &http.Client{Transport: newLoggingTransport(logging, nil)}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", server.URL+"/signin", strings.NewReader(`{"username":"education","password":"s3cret-password"}`))
+	if err != nil {
+		t.Fatalf("unexpected error creating request: %s", err)
+	}
+	req.Header.Set("Authorization", "header-token")
+
+	res, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("unexpected error sending request: %s", err)
+	}
+	defer res.Body.Close()
+
+	// The response body must still be readable after it was logged.
+	var body bytes.Buffer
+	if _, err := body.ReadFrom(res.Body); err != nil {
+		t.Fatalf("unexpected error reading response: %s", err)
+	}
+	if !strings.Contains(body.String(), "issued-token") {
+		t.Errorf("expected response body to be passed through unchanged, got: %s", body.String())
+	}
+
+	if !strings.Contains(output.String(), "/signin") {
+		t.Fatalf("expected request to be logged, got: %s", output.String())
+	}
+
+	assertNoSecrets(t, output.String(), "s3cret-password", "header-token", "issued-token", "cookie-secret")
+}
+
+func TestRedactBody(t *testing.T) {
+	got := redactBody([]byte(`{"items":[{"token":"nested-token"}],"Password":"p"}`))
+
+	assertNoSecrets(t, got, "nested-token", `"p"`)
+
+	if got := redactBody([]byte("not json")); got != "not json" {
+		t.Errorf("expected non-JSON body unchanged, got: %s", got)
+	}
+}
+
+func assertNoSecrets(t *testing.T, output string, secrets ...string) {
+	t.Helper()
+
+	for _, secret := range secrets {
+		if strings.Contains(output, secret) {
+			t.Errorf("secret %q leaked into output: %s", secret, output)
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package fsd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"math/big"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+)
+
+// upgradeFloatPricesV0 upgrades state of schema version 0, in which the
+// items[*].coffee.price attributes were float64, to money amounts. Prices
+// are rounded to moneyScale decimal places to drop binary rounding noise.
+// Every other attribute is kept as is.
+func upgradeFloatPricesV0(_ context.Context, req resource.UpgradeStateRequest, resp *resource.UpgradeStateResponse) {
+	var state map[string]interface{}
+
+	decoder := json.NewDecoder(bytes.NewReader(req.RawState.JSON))
+	decoder.UseNumber()
+	if err := decoder.Decode(&state); err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Upgrade Resource State",
+			"Could not decode the prior resource state: "+err.Error(),
+		)
+		return
+	}
+
+	items, _ := state["items"].([]interface{})
+	for _, item := range items {
+		itemObject, _ := item.(map[string]interface{})
+		coffee, _ := itemObject["coffee"].(map[string]interface{})
+
+		price, ok := coffee["price"].(json.Number)
+		if !ok {
+			continue
+		}
+
+		amount, _, err := big.ParseFloat(price.String(), 10, moneyPrecision, big.ToNearestEven)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Unable to Upgrade Resource State",
+				"Could not convert the prior price "+price.String()+" to a money amount: "+err.Error(),
+			)
+			return
+		}
+		coffee["price"] = json.Number(formatMoney(amount))
+	}
+
+	upgraded, err := json.Marshal(state)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Upgrade Resource State",
+			"Could not encode the upgraded resource state: "+err.Error(),
+		)
+		return
+	}
+
+	resp.DynamicValue = &tfprotov6.DynamicValue{JSON: upgraded}
+}
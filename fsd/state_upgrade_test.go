@@ -0,0 +1,45 @@
+package fsd
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+)
+
+func TestUpgradeFloatPricesV0(t *testing.T) {
+	req := resource.UpgradeStateRequest{
+		RawState: &tfprotov6.RawState{
+			JSON: []byte(`{"id":"1","items":[{"quantity":2,"coffee":{"id":1,"name":"HCP Aeropress","price":3.3000000000000003}}]}`),
+		},
+	}
+	resp := &resource.UpgradeStateResponse{}
+
+	upgradeFloatPricesV0(context.Background(), req, resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected error: %v", resp.Diagnostics)
+	}
+
+	var state struct {
+		ID    string `json:"id"`
+		Items []struct {
+			Quantity int `json:"quantity"`
+			Coffee   struct {
+				Name  string      `json:"name"`
+				Price json.Number `json:"price"`
+			} `json:"coffee"`
+		} `json:"items"`
+	}
+	if err := json.Unmarshal(resp.DynamicValue.JSON, &state); err != nil {
+		t.Fatalf("unexpected error decoding upgraded state: %s", err)
+	}
+
+	if state.ID != "1" || state.Items[0].Quantity != 2 || state.Items[0].Coffee.Name != "HCP Aeropress" {
+		t.Errorf("expected other attributes to be kept, got %s", resp.DynamicValue.JSON)
+	}
+	if price := state.Items[0].Coffee.Price; price != "3.3" {
+		t.Errorf("expected rounded price 3.3, got %s", price)
+	}
+}
@@ -0,0 +1,178 @@
+package fsd
+
+import (
+	"context"
+	"io"
+	"math"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// throttlingTransport limits the rate and concurrency of fsd API requests.
+// Terraform runs several resource operations in parallel against the one
+// client created in Configure, so limits apply across all of them.
+type throttlingTransport struct {
+	logging httpLogging
+	next    http.RoundTripper
+	limiter *tokenBucket
+	slots   chan struct{}
+}
+
+// newThrottlingTransport wraps next so that at most requestsPerSecond
+// requests start per second and at most maxConcurrent are in flight. A zero
+// value disables the respective limit. Waits are logged as described by
+// logging.
+func newThrottlingTransport(logging httpLogging, next http.RoundTripper, requestsPerSecond float64, maxConcurrent int64) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+
+	t := &throttlingTransport{
+		logging: logging,
+		next:    next,
+	}
+	if requestsPerSecond > 0 {
+		t.limiter = newTokenBucket(requestsPerSecond)
+	}
+	if maxConcurrent > 0 {
+		t.slots = make(chan struct{}, maxConcurrent)
+	}
+
+	return t
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *throttlingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+
+	if t.slots != nil {
+		start := time.Now()
+		select {
+		case t.slots <- struct{}{}:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+		t.logWait(req, "max_concurrent_requests", time.Since(start))
+	}
+
+	if t.limiter != nil {
+		wait, err := t.limiter.wait(ctx)
+		t.logWait(req, "requests_per_second", wait)
+		if err != nil {
+			t.release()
+			return nil, err
+		}
+	}
+
+	res, err := t.next.RoundTrip(req)
+	if err != nil {
+		t.release()
+		return nil, err
+	}
+
+	// Keep the slot until the caller is done with the response.
+	if t.slots != nil {
+		res.Body = &releasingBody{ReadCloser: res.Body, release: t.release}
+	}
+
+	return res, nil
+}
+
+func (t *throttlingTransport) release() {
+	if t.slots != nil {
+		<-t.slots
+	}
+}
+
+// logWait records throttled waits so operators can tune the limits.
+func (t *throttlingTransport) logWait(req *http.Request, limit string, wait time.Duration) {
+	if wait < time.Millisecond {
+		return
+	}
+
+	tflog.SubsystemDebug(t.logging.context(req.Context()), httpLogSubsystem, "Throttled fsd API request", map[string]any{
+		"http_method": req.Method,
+		"http_url":    req.URL.String(),
+		"limit":       limit,
+		"wait_ms":     wait.Milliseconds(),
+	})
+}
+
+// releasingBody frees a concurrency slot when the response body is closed.
+type releasingBody struct {
+	io.ReadCloser
+
+	once    sync.Once
+	release func()
+}
+
+// Close implements io.Closer.
+func (b *releasingBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.once.Do(b.release)
+	return err
+}
+
+// tokenBucket is a token bucket rate limiter that allows bursts of up to
+// one second worth of requests.
+type tokenBucket struct {
+	mu     sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+	now    func() time.Time
+}
+
+// newTokenBucket returns a full bucket refilled at rate tokens per second.
+func newTokenBucket(rate float64) *tokenBucket {
+	burst := math.Max(1, math.Ceil(rate))
+
+	return &tokenBucket{
+		rate:   rate,
+		burst:  burst,
+		tokens: burst,
+		last:   time.Now(),
+		now:    time.Now,
+	}
+}
+
+// reserve takes a token and returns how long the caller must wait before
+// using it.
+func (b *tokenBucket) reserve() time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := b.now()
+	b.tokens = math.Min(b.burst, b.tokens+now.Sub(b.last).Seconds()*b.rate)
+	b.last = now
+	b.tokens--
+
+	if b.tokens >= 0 {
+		return 0
+	}
+
+	return time.Duration(-b.tokens / b.rate * float64(time.Second))
+}
+
+// wait blocks until the caller may send a request or ctx is done, and
+// returns how long it waited.
+func (b *tokenBucket) wait(ctx context.Context) (time.Duration, error) {
+	delay := b.reserve()
+	if delay <= 0 {
+		return 0, nil
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return delay, nil
+	case <-ctx.Done():
+		return delay, ctx.Err()
+	}
+}
@@ -0,0 +1,157 @@
+package fsd
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflogtest"
+)
+
+func TestTokenBucket(t *testing.T) {
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	bucket := newTokenBucket(2)
+	bucket.now = func() time.Time { return now }
+	bucket.last = now
+
+	// A full bucket allows a burst of one second worth of requests.
+	for i := 0; i < 2; i++ {
+		if wait := bucket.reserve(); wait != 0 {
+			t.Fatalf("request %d: expected no wait, got %s", i+1, wait)
+		}
+	}
+
+	if wait := bucket.reserve(); wait != 500*time.Millisecond {
+		t.Errorf("expected 500ms wait once the bucket is empty, got %s", wait)
+	}
+	if wait := bucket.reserve(); wait != time.Second {
+		t.Errorf("expected waits to queue up, got %s", wait)
+	}
+
+	now = now.Add(10 * time.Second)
+	if wait := bucket.reserve(); wait != 0 {
+		t.Errorf("expected refilled bucket to allow requests, got %s", wait)
+	}
+}
+
+func TestThrottlingTransportLimitsConcurrency(t *testing.T) {
+	var inFlight, peak atomic.Int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		current := inFlight.Add(1)
+		defer inFlight.Add(-1)
+
+		for {
+			seen := peak.Load()
+			if current <= seen || peak.CompareAndSwap(seen, current) {
+				break
+			}
+		}
+
+		time.Sleep(20 * time.Millisecond)
+		w.Write([]byte(`[]`))
+	}))
+	defer server.Close()
+
+	// The requests log concurrently, so the buffer needs a lock.
+	var output lockedBuffer
+	ctx := tflogtest.RootLogger(context.Background(), &output)
+
+	client := &http.Client{Transport: newThrottlingTransport(httpLogging{}, nil, 0, 2)}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			req, err := http.NewRequestWithContext(ctx, "GET", server.URL, nil)
+			if err != nil {
+				t.Errorf("unexpected error creating request: %s", err)
+				return
+			}
+
+			res, err := client.Do(req)
+			if err != nil {
+				t.Errorf("unexpected error: %s", err)
+				return
+			}
+			res.Body.Close()
+		}()
+	}
+	wg.Wait()
+
+	if got := peak.Load(); got > 2 {
+		t.Errorf("expected at most 2 requests in flight, got %d", got)
+	}
+
+	if !strings.Contains(output.String(), "Throttled fsd API request") {
+		t.Errorf("expected throttled waits to be logged, got: %s", output.String())
+	}
+}
+
+func TestThrottlingTransportLimitsRate(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`[]`))
+	}))
+	defer server.Close()
+
+	client := &http.Client{Transport: newThrottlingTransport(httpLogging{}, nil, 20, 0)}
+
+	// The first 20 requests use the burst, the next 5 wait 50ms each.
+	start := time.Now()
+	for i := 0; i < 25; i++ {
+		res, err := client.Get(server.URL)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		res.Body.Close()
+	}
+
+	if elapsed := time.Since(start); elapsed < 200*time.Millisecond {
+		t.Errorf("expected requests to be rate limited, took %s", elapsed)
+	}
+}
+
+func TestThrottlingTransportHonorsContext(t *testing.T) {
+	transport := newThrottlingTransport(httpLogging{}, nil, 0, 1).(*throttlingTransport)
+	transport.slots <- struct{}{}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, "GET", "http://localhost/coffees", nil)
+	if err != nil {
+		t.Fatalf("unexpected error creating request: %s", err)
+	}
+
+	if _, err := transport.RoundTrip(req); err != context.DeadlineExceeded {
+		t.Errorf("expected waiting for a slot to stop at the deadline, got: %v", err)
+	}
+}
+
+// lockedBuffer is a bytes.Buffer that is safe for concurrent writers.
+type lockedBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *lockedBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.buf.Write(p)
+}
+
+func (b *lockedBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.buf.String()
+}
@@ -0,0 +1,191 @@
+package fsd
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const (
+	// tokenCacheDefaultTTL is how long a cached token is trusted when the
+	// token itself does not carry an expiry.
+	tokenCacheDefaultTTL = 30 * time.Minute
+
+	// tokenCacheExpiryMargin treats tokens as expired slightly early, so a
+	// token does not run out in the middle of a Terraform command.
+	tokenCacheExpiryMargin = time.Minute
+)
+
+// tokenCache stores API tokens on disk so that the provider processes
+// Terraform starts during one run do not all sign in again. Entries are
+// keyed by host and username and the file is only readable by its owner.
+type tokenCache struct {
+	path string
+	now  func() time.Time
+}
+
+// tokenCacheEntry is a cached token and the time it stops being used.
+type tokenCacheEntry struct {
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// newTokenCache returns a cache backed by the file at path.
+func newTokenCache(path string) *tokenCache {
+	return &tokenCache{
+		path: path,
+		now:  time.Now,
+	}
+}
+
+// get returns the cached token for host and username if it is still valid.
+func (c *tokenCache) get(host, username string) (string, bool, error) {
+	entries, err := c.load()
+	if err != nil {
+		return "", false, err
+	}
+
+	entry, ok := entries[tokenCacheKey(host, username)]
+	if !ok || !c.valid(entry) {
+		return "", false, nil
+	}
+
+	return entry.Token, true, nil
+}
+
+// put caches token for host and username and drops expired entries.
+func (c *tokenCache) put(host, username, token string) error {
+	entries, err := c.load()
+	if err != nil {
+		entries = map[string]tokenCacheEntry{}
+	}
+
+	for key, entry := range entries {
+		if !c.valid(entry) {
+			delete(entries, key)
+		}
+	}
+
+	entries[tokenCacheKey(host, username)] = tokenCacheEntry{
+		Token:     token,
+		ExpiresAt: c.expiry(token),
+	}
+
+	return c.save(entries)
+}
+
+// invalidate removes the cached token for host and username.
+func (c *tokenCache) invalidate(host, username string) error {
+	entries, err := c.load()
+	if err != nil {
+		return err
+	}
+
+	key := tokenCacheKey(host, username)
+	if _, ok := entries[key]; !ok {
+		return nil
+	}
+	delete(entries, key)
+
+	return c.save(entries)
+}
+
+func (c *tokenCache) valid(entry tokenCacheEntry) bool {
+	return entry.Token != "" && c.now().Add(tokenCacheExpiryMargin).Before(entry.ExpiresAt)
+}
+
+// expiry returns the exp claim of a JWT token, or the default TTL for
+// tokens that do not carry one.
+func (c *tokenCache) expiry(token string) time.Time {
+	parts := strings.Split(token, ".")
+	if len(parts) == 3 {
+		payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+		if err == nil {
+			var claims struct {
+				Exp int64 `json:"exp"`
+			}
+			if err := json.Unmarshal(payload, &claims); err == nil && claims.Exp > 0 {
+				return time.Unix(claims.Exp, 0)
+			}
+		}
+	}
+
+	return c.now().Add(tokenCacheDefaultTTL)
+}
+
+func (c *tokenCache) load() (map[string]tokenCacheEntry, error) {
+	entries := map[string]tokenCacheEntry{}
+
+	info, err := os.Stat(c.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return entries, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Refuse to trust tokens that other users could have read or planted.
+	if info.Mode().Perm()&0o077 != 0 {
+		return nil, fmt.Errorf("token cache %s must only be accessible by its owner, has mode %s", c.path, info.Mode().Perm())
+	}
+
+	data, err := os.ReadFile(c.path)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("token cache %s is corrupt: %w", c.path, err)
+	}
+
+	return entries, nil
+}
+
+func (c *tokenCache) save(entries map[string]tokenCacheEntry) error {
+	data, err := json.Marshal(entries)
+	if err != nil {
+		return err
+	}
+
+	dir := filepath.Dir(c.path)
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return err
+	}
+
+	// Write to a private temporary file and rename it into place so that
+	// concurrent provider processes never see a partial file.
+	tmp, err := os.CreateTemp(dir, filepath.Base(c.path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if err := tmp.Chmod(0o600); err != nil {
+		tmp.Close()
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), c.path)
+}
+
+// tokenCacheKey identifies the cache entry of a host and username without
+// storing either in the file.
+func tokenCacheKey(host, username string) string {
+	sum := sha256.Sum256([]byte(host + "\x00" + username))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,151 @@
+package fsd
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	typs "github.com/gofsd/fsd-types"
+)
+
+func TestTokenCache(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache", "tokens.json")
+	cache := newTokenCache(path)
+
+	if _, ok, err := cache.get("http://localhost", "education"); ok || err != nil {
+		t.Fatalf("expected miss on missing file, got ok=%t err=%v", ok, err)
+	}
+
+	if err := cache.put("http://localhost", "education", "opaque-token"); err != nil {
+		t.Fatalf("unexpected error caching token: %s", err)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("expected cache file to exist: %s", err)
+	}
+	if perm := info.Mode().Perm(); perm != 0o600 {
+		t.Errorf("expected cache file mode 0600, got %s", perm)
+	}
+
+	token, ok, err := cache.get("http://localhost", "education")
+	if err != nil || !ok || token != "opaque-token" {
+		t.Errorf("expected cached token, got %q ok=%t err=%v", token, ok, err)
+	}
+
+	if _, ok, _ := cache.get("http://localhost", "other"); ok {
+		t.Errorf("expected tokens to be keyed by username")
+	}
+	if _, ok, _ := cache.get("http://elsewhere", "education"); ok {
+		t.Errorf("expected tokens to be keyed by host")
+	}
+
+	if err := cache.invalidate("http://localhost", "education"); err != nil {
+		t.Fatalf("unexpected error invalidating token: %s", err)
+	}
+	if _, ok, _ := cache.get("http://localhost", "education"); ok {
+		t.Errorf("expected invalidated token to be gone")
+	}
+}
+
+func TestTokenCacheExpiry(t *testing.T) {
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	cache := newTokenCache(filepath.Join(t.TempDir(), "tokens.json"))
+	cache.now = func() time.Time { return now }
+
+	claims := base64.RawURLEncoding.EncodeToString([]byte(fmt.Sprintf(`{"exp":%d}`, now.Add(10*time.Minute).Unix())))
+	jwt := "header." + claims + ".signature"
+
+	if err := cache.put("host", "jwt-user", jwt); err != nil {
+		t.Fatalf("unexpected error caching token: %s", err)
+	}
+	if err := cache.put("host", "opaque-user", "opaque-token"); err != nil {
+		t.Fatalf("unexpected error caching token: %s", err)
+	}
+
+	now = now.Add(9 * time.Minute)
+	if _, ok, _ := cache.get("host", "jwt-user"); ok {
+		t.Errorf("expected JWT within the expiry margin to be treated as expired")
+	}
+	if _, ok, _ := cache.get("host", "opaque-user"); !ok {
+		t.Errorf("expected opaque token to use the default TTL")
+	}
+
+	now = now.Add(tokenCacheDefaultTTL)
+	if _, ok, _ := cache.get("host", "opaque-user"); ok {
+		t.Errorf("expected opaque token to expire after the default TTL")
+	}
+}
+
+func TestTokenCacheRejectsOpenPermissions(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tokens.json")
+	if err := os.WriteFile(path, []byte(`{}`), 0o644); err != nil {
+		t.Fatalf("unexpected error writing cache: %s", err)
+	}
+
+	if _, _, err := newTokenCache(path).get("host", "education"); err == nil {
+		t.Errorf("expected error for world-readable cache file")
+	}
+}
+
+func TestClientAuthenticateUsesTokenCache(t *testing.T) {
+	var signIns atomic.Int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/signin":
+			signIns.Add(1)
+			w.Write([]byte(`{"user_id":1,"username":"education","token":"fresh-token"}`))
+		default:
+			if r.Header.Get("Authorization") != "fresh-token" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			w.Write([]byte(`[]`))
+		}
+	}))
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "tokens.json")
+	newCachingClient := func() *fsdClient {
+		client := newTestClient(server.URL)
+		client.Auth = typs.AuthStruct{Username: "education", Password: "test123"}
+		client.tokenCache = newTokenCache(path)
+		return client
+	}
+
+	// Simulate several provider processes in one Terraform run.
+	for i := 0; i < 3; i++ {
+		if err := newCachingClient().authenticate(context.Background()); err != nil {
+			t.Fatalf("unexpected error authenticating: %s", err)
+		}
+	}
+	if got := signIns.Load(); got != 1 {
+		t.Errorf("expected one sign-in, got %d", got)
+	}
+
+	// A token the API rejects is replaced in the cache.
+	if err := newTokenCache(path).put(server.URL, "education", "revoked-token"); err != nil {
+		t.Fatalf("unexpected error caching token: %s", err)
+	}
+	client := newCachingClient()
+	if err := client.authenticate(context.Background()); err != nil {
+		t.Fatalf("unexpected error authenticating: %s", err)
+	}
+	if _, err := client.GetCoffees(context.Background()); err != nil {
+		t.Fatalf("unexpected error after re-authentication: %s", err)
+	}
+	if token, _, _ := newTokenCache(path).get(server.URL, "education"); token != "fresh-token" {
+		t.Errorf("expected rejected token to be replaced, got %q", token)
+	}
+	if got := signIns.Load(); got != 2 {
+		t.Errorf("expected a second sign-in for the rejected token, got %d", got)
+	}
+}
@@ -0,0 +1,204 @@
+package fsd
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+const (
+	// httpLogSubsystem is the tflog subsystem fsd API traffic is logged
+	// under. Its level is controlled with TF_LOG_PROVIDER_FSD_HTTP.
+	httpLogSubsystem = "fsd_http"
+
+	// httpLogMaxBodySize is the number of body bytes logged at TRACE level.
+	httpLogMaxBodySize = 4096
+
+	// requestIDHeader carries the request ID sent with every API request.
+	requestIDHeader = "X-Request-Id"
+)
+
+// withHTTPLogging returns a context carrying the fsd_http logging subsystem.
+// Fields already set on ctx, such as fsd_host, are copied to the subsystem.
+func withHTTPLogging(ctx context.Context) context.Context {
+	return tflog.NewSubsystem(ctx, httpLogSubsystem,
+		tflog.WithLevelFromEnv("TF_LOG_PROVIDER_FSD", "HTTP"),
+		tflog.WithRootFields(),
+	)
+}
+
+// httpTraceEnabled reports whether the environment sets the fsd_http
+// subsystem to TRACE, the only level bodies are logged at. The most specific
+// variable that is set wins, as it does for the logger levels themselves;
+// TF_LOG=JSON also logs everything at TRACE.
+func httpTraceEnabled() bool {
+	for _, name := range []string{"TF_LOG_PROVIDER_FSD_HTTP", "TF_LOG_PROVIDER_FSD", "TF_LOG_PROVIDER", "TF_LOG"} {
+		level := strings.ToUpper(os.Getenv(name))
+		if level != "" {
+			return level == "TRACE" || (name == "TF_LOG" && level == "JSON")
+		}
+	}
+
+	return false
+}
+
+// httpLogging holds the provider-level log fields and secrets captured in
+// Configure. The client outlives the Configure call, so they are applied to
+// the context of each request rather than kept in a context of their own;
+// entries then also carry the fields of the Terraform operation that sent
+// the request. Bodies are only read for logging when traceBodies is set, so
+// responses are not buffered in memory unless they are logged.
+type httpLogging struct {
+	fields      map[string]any
+	secrets     []string
+	traceBodies bool
+}
+
+// context returns ctx with the logging fields set, the fsd_http subsystem
+// created and the secrets masked.
+func (l httpLogging) context(ctx context.Context) context.Context {
+	for key, value := range l.fields {
+		ctx = tflog.SetField(ctx, key, value)
+	}
+	ctx = withHTTPLogging(ctx)
+
+	return maskSecrets(ctx, l.secrets...)
+}
+
+// loggingTransport logs every fsd API request and response under the
+// fsd_http subsystem with secrets redacted.
+type loggingTransport struct {
+	logging httpLogging
+	next    http.RoundTripper
+}
+
+// newLoggingTransport wraps next so its traffic is logged with the logger
+// of each request context, set up as described by logging.
+func newLoggingTransport(logging httpLogging, next http.RoundTripper) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+
+	return &loggingTransport{
+		logging: logging,
+		next:    next,
+	}
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *loggingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := t.logging.context(req.Context())
+
+	// RoundTrip must not modify the caller's request.
+	req = req.Clone(req.Context())
+	requestID := req.Header.Get(requestIDHeader)
+	if requestID == "" {
+		requestID = newRequestID()
+		req.Header.Set(requestIDHeader, requestID)
+	}
+
+	ctx = tflog.SubsystemSetField(ctx, httpLogSubsystem, "http_method", req.Method)
+	ctx = tflog.SubsystemSetField(ctx, httpLogSubsystem, "http_url", req.URL.String())
+	ctx = tflog.SubsystemSetField(ctx, httpLogSubsystem, "request_id", requestID)
+
+	var reqBody []byte
+	if t.logging.traceBodies {
+		var err error
+		reqBody, err = peekBody(&req.Body)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	tflog.SubsystemDebug(ctx, httpLogSubsystem, "Sending fsd API request")
+	tflog.SubsystemTrace(ctx, httpLogSubsystem, "fsd API request details", map[string]any{
+		"http_headers": redactHeaders(req.Header),
+		"http_body":    truncateBody(redactBody(reqBody)),
+	})
+
+	start := time.Now()
+	res, err := t.next.RoundTrip(req)
+	duration := time.Since(start)
+	if err != nil {
+		tflog.SubsystemDebug(ctx, httpLogSubsystem, "fsd API request failed", map[string]any{
+			"duration_ms": duration.Milliseconds(),
+			"error":       err.Error(),
+		})
+		return nil, err
+	}
+
+	// Prefer the ID the server assigned, if it reports one.
+	if serverID := res.Header.Get(requestIDHeader); serverID != "" && serverID != requestID {
+		ctx = tflog.SubsystemSetField(ctx, httpLogSubsystem, "server_request_id", serverID)
+	}
+
+	var resBody []byte
+	if t.logging.traceBodies {
+		resBody, err = peekBody(&res.Body)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	tflog.SubsystemDebug(ctx, httpLogSubsystem, "Received fsd API response", map[string]any{
+		"http_status": res.StatusCode,
+		"duration_ms": duration.Milliseconds(),
+	})
+	tflog.SubsystemTrace(ctx, httpLogSubsystem, "fsd API response details", map[string]any{
+		"http_headers": redactHeaders(res.Header),
+		"http_body":    truncateBody(redactBody(resBody)),
+	})
+
+	return res, nil
+}
+
+// peekBody reads the body and replaces it with an unread copy.
+func peekBody(body *io.ReadCloser) ([]byte, error) {
+	if *body == nil || *body == http.NoBody {
+		return nil, nil
+	}
+
+	data, err := io.ReadAll(*body)
+	(*body).Close()
+	if err != nil {
+		return nil, err
+	}
+
+	*body = io.NopCloser(bytes.NewReader(data))
+
+	return data, nil
+}
+
+// truncateBody limits a logged body to httpLogMaxBodySize bytes, cutting
+// before the character that crosses the limit rather than through it.
+func truncateBody(body string) string {
+	if len(body) <= httpLogMaxBodySize {
+		return body
+	}
+
+	n := httpLogMaxBodySize
+	for n > 0 && !utf8.RuneStart(body[n]) {
+		n--
+	}
+
+	return body[:n] + "...(truncated)"
+}
+
+// newRequestID returns a random identifier for correlating log entries.
+func newRequestID() string {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return "unknown"
+	}
+
+	return hex.EncodeToString(b)
+}
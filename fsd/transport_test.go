@@ -0,0 +1,121 @@
+package fsd
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-log/tflogtest"
+)
+
+func TestLoggingTransport(t *testing.T) {
+	largeBody := `{"description":"` + strings.Repeat("x", 2*httpLogMaxBodySize) + `"}`
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get(requestIDHeader) == "" {
+			t.Errorf("expected %s header to be sent", requestIDHeader)
+		}
+		w.Write([]byte(largeBody))
+	}))
+	defer server.Close()
+
+	var output bytes.Buffer
+
+	// Entries must carry the fields of the operation that sent the request.
+	ctx := tflogtest.RootLogger(context.Background(), &output)
+	ctx = tflog.SetField(ctx, "tf_req_id", "test-request")
+
+	logging := httpLogging{fields: map[string]any{"fsd_host": server.URL}, traceBodies: true}
+	client := &http.Client{Transport: newLoggingTransport(logging, nil)}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", server.URL+"/coffees", nil)
+	if err != nil {
+		t.Fatalf("unexpected error creating request: %s", err)
+	}
+
+	res, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("unexpected error sending request: %s", err)
+	}
+	res.Body.Close()
+
+	entries, err := tflogtest.MultilineJSONDecode(&output)
+	if err != nil {
+		t.Fatalf("unexpected error decoding logs: %s", err)
+	}
+
+	var response map[string]any
+	var loggedBody bool
+	for _, entry := range entries {
+		if entry["@message"] == "Received fsd API response" {
+			response = entry
+		}
+		if entry["@message"] == "fsd API response details" {
+			body, _ := entry["http_body"].(string)
+			loggedBody = strings.HasPrefix(body, `{"description":"xxx`)
+			if len(body) > httpLogMaxBodySize+len("...(truncated)") {
+				t.Errorf("expected logged body to be truncated, got %d bytes", len(body))
+			}
+		}
+	}
+
+	if response == nil {
+		t.Fatalf("expected a response log entry, got: %v", entries)
+	}
+	if !loggedBody {
+		t.Errorf("expected the response body to be logged, got: %v", entries)
+	}
+
+	if got := response["@module"]; got != "provider."+httpLogSubsystem {
+		t.Errorf("expected entry in %s subsystem, got: %v", httpLogSubsystem, got)
+	}
+
+	for _, key := range []string{"fsd_host", "tf_req_id", "http_method", "http_url", "request_id", "duration_ms"} {
+		if _, ok := response[key]; !ok {
+			t.Errorf("expected %s field in response log entry: %v", key, response)
+		}
+	}
+
+	if got := response["http_status"]; got != float64(http.StatusOK) {
+		t.Errorf("expected http_status 200, got: %v", got)
+	}
+}
+
+func TestTruncateBody(t *testing.T) {
+	// A two-byte character straddles the limit.
+	body := strings.Repeat("x", httpLogMaxBodySize-1) + "é" + "tail"
+
+	got := truncateBody(body)
+	want := strings.Repeat("x", httpLogMaxBodySize-1) + "...(truncated)"
+	if got != want {
+		t.Errorf("expected truncation before the split character, got %q", got[len(got)-20:])
+	}
+
+	if got := truncateBody("short é"); got != "short é" {
+		t.Errorf("expected short body unchanged, got %q", got)
+	}
+}
+
+func TestHTTPTraceEnabled(t *testing.T) {
+	for _, name := range []string{"TF_LOG_PROVIDER_FSD_HTTP", "TF_LOG_PROVIDER_FSD", "TF_LOG_PROVIDER", "TF_LOG"} {
+		t.Setenv(name, "")
+	}
+	if httpTraceEnabled() {
+		t.Errorf("expected body logging to be off without log levels set")
+	}
+
+	t.Setenv("TF_LOG", "trace")
+	if !httpTraceEnabled() {
+		t.Errorf("expected body logging with TF_LOG=trace")
+	}
+
+	// The subsystem level overrides the general one.
+	t.Setenv("TF_LOG_PROVIDER_FSD_HTTP", "DEBUG")
+	if httpTraceEnabled() {
+		t.Errorf("expected no body logging with TF_LOG_PROVIDER_FSD_HTTP=DEBUG")
+	}
+}
@@ -3,7 +3,6 @@ package fsd
 import (
 	"context"
 
-	typs "github.com/gofsd/fsd-types"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
@@ -14,15 +13,21 @@ import (
 
 // Ensure the implementation satisfies the expected interfaces.
 var (
-	_ resource.Resource                = &tryResource{}
-	_ resource.ResourceWithConfigure   = &tryResource{}
-	_ resource.ResourceWithImportState = &tryResource{}
+	_ resource.Resource                 = &tryResource{}
+	_ resource.ResourceWithConfigure    = &tryResource{}
+	_ resource.ResourceWithImportState  = &tryResource{}
+	_ resource.ResourceWithModifyPlan   = &tryResource{}
+	_ resource.ResourceWithUpgradeState = &tryResource{}
 )
 
 // tryResourceModel maps the resource schema data.
 type tryResourceModel struct {
 	ID          types.String   `tfsdk:"id"`
 	Items       []tryItemModel `tfsdk:"items"`
+	Labels      types.Map      `tfsdk:"labels"`
+	LabelsAll   types.Map      `tfsdk:"labels_all"`
+	CreatedAt   types.String   `tfsdk:"created_at"`
+	UpdatedAt   types.String   `tfsdk:"updated_at"`
 	LastUpdated types.String   `tfsdk:"last_updated"`
 }
 
@@ -34,12 +39,12 @@ type tryItemModel struct {
 
 // tryItemCoffeeModel maps coffee try item data.
 type tryItemCoffeeModel struct {
-	ID          types.Int64   `tfsdk:"id"`
-	Name        types.String  `tfsdk:"name"`
-	Teaser      types.String  `tfsdk:"teaser"`
-	Description types.String  `tfsdk:"description"`
-	Price       types.Float64 `tfsdk:"price"`
-	Image       types.String  `tfsdk:"image"`
+	ID          types.Int64  `tfsdk:"id"`
+	Name        types.String `tfsdk:"name"`
+	Teaser      types.String `tfsdk:"teaser"`
+	Description types.String `tfsdk:"description"`
+	Price       moneyValue   `tfsdk:"price"`
+	Image       types.String `tfsdk:"image"`
 }
 
 // NewTryResource is a helper function to simplify the provider implementation.
@@ -49,7 +54,10 @@ func NewTryResource() resource.Resource {
 
 // tryResource is the resource implementation.
 type tryResource struct {
-	client *typs.Client
+	client        *fsdClient
+	defaultLabels map[string]string
+	catalog       *catalogCache
+	coffeePolicy  coffeePolicy
 }
 
 // Configure adds the provider configured client to the resource.
@@ -58,7 +66,11 @@ func (r *tryResource) Configure(_ context.Context, req resource.ConfigureRequest
 		return
 	}
 
-	r.client = req.ProviderData.(*typs.Client)
+	providerData := req.ProviderData.(*fsdProviderData)
+	r.client = providerData.client
+	r.defaultLabels = providerData.defaultLabels
+	r.catalog = providerData.catalog
+	r.coffeePolicy = providerData.coffeePolicy
 }
 
 // Metadata returns the resource type name.
@@ -70,6 +82,7 @@ func (r *tryResource) Metadata(_ context.Context, req resource.MetadataRequest,
 func (r *tryResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
 	resp.Schema = schema.Schema{
 		Description: "Manages an try.",
+		Version:     1,
 		Attributes: map[string]schema.Attribute{
 			"id": schema.StringAttribute{
 				Description: "Numeric identifier of the try.",
@@ -78,9 +91,31 @@ func (r *tryResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *
 					stringplanmodifier.UseStateForUnknown(),
 				},
 			},
-			"last_updated": schema.StringAttribute{
-				Description: "Timestamp of the last Terraform update of the try.",
+			"labels": schema.MapAttribute{
+				Description: "Labels of the try, such as team or cost center. Merged over the provider default_labels.",
+				ElementType: types.StringType,
+				Optional:    true,
+			},
+			"labels_all": schema.MapAttribute{
+				Description: "Effective labels of the try: the provider default_labels merged with labels.",
+				ElementType: types.StringType,
+				Computed:    true,
+			},
+			"created_at": schema.StringAttribute{
+				Description: "RFC 3339 timestamp of the try creation, as recorded by the fsd API.",
 				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"updated_at": schema.StringAttribute{
+				Description: "RFC 3339 timestamp of the last update of the try, as recorded by the fsd API.",
+				Computed:    true,
+			},
+			"last_updated": schema.StringAttribute{
+				Description:        "Deprecated alias of updated_at.",
+				DeprecationMessage: "Use updated_at instead. The last_updated attribute will be removed in a future release.",
+				Computed:           true,
 			},
 			"items": schema.ListNestedAttribute{
 				Description: "List of items in the try.",
@@ -111,8 +146,9 @@ func (r *tryResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *
 									Description: "Product description of the coffee.",
 									Computed:    true,
 								},
-								"price": schema.Float64Attribute{
+								"price": schema.NumberAttribute{
 									Description: "Suggested cost of the coffee.",
+									CustomType:  moneyType{},
 									Computed:    true,
 								},
 								"image": schema.StringAttribute{
@@ -128,6 +164,25 @@ func (r *tryResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *
 	}
 }
 
+// UpgradeState upgrades the state of earlier schema versions.
+func (r *tryResource) UpgradeState(_ context.Context) map[int64]resource.StateUpgrader {
+	return map[int64]resource.StateUpgrader{
+		// Version 0 stored prices as float64.
+		0: {StateUpgrader: upgradeFloatPricesV0},
+	}
+}
+
+// ModifyPlan merges the provider default labels into the planned labels_all
+// and checks the items against the allowed coffees.
+func (r *tryResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	modifyPlanLabels(ctx, r.defaultLabels, req, resp)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	modifyPlanCoffeePolicy(ctx, r.coffeePolicy, r.catalog, req, resp)
+}
+
 // Create a new resource
 func (r *tryResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
 	// Retrieve values from plan
@@ -150,7 +205,7 @@ func (r *tryResource) Create(ctx context.Context, req resource.CreateRequest, re
 	// }
 
 	// // Create new try
-	// try, err := r.client.CreateTry(items)
+	// try, err := r.client.CreateTry(ctx, items)
 	// if err != nil {
 	// 	resp.Diagnostics.AddError(
 	// 		"Error creating try",
@@ -168,13 +223,15 @@ func (r *tryResource) Create(ctx context.Context, req resource.CreateRequest, re
 	// 			Name:        types.StringValue(tryItem.Coffee.Name),
 	// 			Teaser:      types.StringValue(tryItem.Coffee.Teaser),
 	// 			Description: types.StringValue(tryItem.Coffee.Description),
-	// 			Price:       types.Float64Value(tryItem.Coffee.Price),
+	// 			Price:       moneyFromFloat64(tryItem.Coffee.Price),
 	// 			Image:       types.StringValue(tryItem.Coffee.Image),
 	// 		},
 	// 		Quantity: types.Int64Value(int64(tryItem.Quantity)),
 	// 	}
 	// }
-	// plan.LastUpdated = types.StringValue(time.Now().Format(time.RFC850))
+	// plan.CreatedAt = timestampValue(try.CreatedAt)
+	// plan.UpdatedAt = timestampValue(try.UpdatedAt)
+	// plan.LastUpdated = plan.UpdatedAt
 
 	// Set state to fully populated data
 	diags = resp.State.Set(ctx, plan)
@@ -195,7 +252,7 @@ func (r *tryResource) Read(ctx context.Context, req resource.ReadRequest, resp *
 	}
 
 	// Get refreshed try value from fsd
-	// try, err := r.client.GetTry(state.ID.ValueString())
+	// try, err := r.client.GetTry(ctx, state.ID.ValueString())
 	// if err != nil {
 	// 	resp.Diagnostics.AddError(
 	// 		"Error Reading fsd try",
@@ -213,12 +270,15 @@ func (r *tryResource) Read(ctx context.Context, req resource.ReadRequest, resp *
 	// 			Name:        types.StringValue(item.Coffee.Name),
 	// 			Teaser:      types.StringValue(item.Coffee.Teaser),
 	// 			Description: types.StringValue(item.Coffee.Description),
-	// 			Price:       types.Float64Value(item.Coffee.Price),
+	// 			Price:       moneyFromFloat64(item.Coffee.Price),
 	// 			Image:       types.StringValue(item.Coffee.Image),
 	// 		},
 	// 		Quantity: types.Int64Value(int64(item.Quantity)),
 	// 	})
 	// }
+	// state.CreatedAt = timestampValue(try.CreatedAt)
+	// state.UpdatedAt = timestampValue(try.UpdatedAt)
+	// state.LastUpdated = state.UpdatedAt
 
 	// Set refreshed state
 	diags = resp.State.Set(ctx, &state)
@@ -249,7 +309,7 @@ func (r *tryResource) Update(ctx context.Context, req resource.UpdateRequest, re
 	// }
 
 	// // Update existing try
-	// _, err := r.client.UpdateTry(plan.ID.ValueString(), fsdItems)
+	// _, err := r.client.UpdateTry(ctx, plan.ID.ValueString(), fsdItems)
 	// if err != nil {
 	// 	resp.Diagnostics.AddError(
 	// 		"Error Updating fsd try",
@@ -260,7 +320,7 @@ func (r *tryResource) Update(ctx context.Context, req resource.UpdateRequest, re
 
 	// // Fetch updated items from Gettry as Updatetry items are not
 	// // populated.
-	// try, err := r.client.GetTry(plan.ID.ValueString())
+	// try, err := r.client.GetTry(ctx, plan.ID.ValueString())
 	// if err != nil {
 	// 	resp.Diagnostics.AddError(
 	// 		"Error Reading fsd try",
@@ -278,13 +338,15 @@ func (r *tryResource) Update(ctx context.Context, req resource.UpdateRequest, re
 	// 			Name:        types.StringValue(item.Coffee.Name),
 	// 			Teaser:      types.StringValue(item.Coffee.Teaser),
 	// 			Description: types.StringValue(item.Coffee.Description),
-	// 			Price:       types.Float64Value(item.Coffee.Price),
+	// 			Price:       moneyFromFloat64(item.Coffee.Price),
 	// 			Image:       types.StringValue(item.Coffee.Image),
 	// 		},
 	// 		Quantity: types.Int64Value(int64(item.Quantity)),
 	// 	})
 	// }
-	// plan.LastUpdated = types.StringValue(time.Now().Format(time.RFC850))
+	// plan.CreatedAt = timestampValue(try.CreatedAt)
+	// plan.UpdatedAt = timestampValue(try.UpdatedAt)
+	// plan.LastUpdated = plan.UpdatedAt
 
 	diags = resp.State.Set(ctx, plan)
 	resp.Diagnostics.Append(diags...)
@@ -303,7 +365,7 @@ func (r *tryResource) Delete(ctx context.Context, req resource.DeleteRequest, re
 	}
 
 	// Delete existing try
-	// err := r.client.DeleteTry(state.ID.ValueString())
+	// err := r.client.DeleteTry(ctx, state.ID.ValueString())
 	// if err != nil {
 	// 	resp.Diagnostics.AddError(
 	// 		"Error Deleting fsd try",
@@ -316,4 +378,8 @@ func (r *tryResource) Delete(ctx context.Context, req resource.DeleteRequest, re
 func (r *tryResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
 	// Retrieve import ID and save to id attribute
 	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+
+	labelsAll, diags := defaultLabelsValue(ctx, r.defaultLabels)
+	resp.Diagnostics.Append(diags...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("labels_all"), labelsAll)...)
 }
@@ -3,7 +3,6 @@ package fsd
 import (
 	"context"
 
-	typs "github.com/gofsd/fsd-types"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/types"
@@ -22,7 +21,7 @@ func NewTryDataSource() datasource.DataSource {
 
 // tryDataSource is the data source implementation.
 type tryDataSource struct {
-	client *typs.Client
+	client *fsdClient
 }
 
 // tryDataSourceModel maps the data source schema data.
@@ -37,7 +36,7 @@ type tryModel struct {
 	Name        types.String          `tfsdk:"name"`
 	Teaser      types.String          `tfsdk:"teaser"`
 	Description types.String          `tfsdk:"description"`
-	Price       types.Float64         `tfsdk:"price"`
+	Price       moneyValue            `tfsdk:"price"`
 	Image       types.String          `tfsdk:"image"`
 	Ingredients []tryIngredientsModel `tfsdk:"ingredients"`
 }
@@ -82,8 +81,9 @@ func (d *tryDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, re
 							Description: "Product description of the coffee.",
 							Computed:    true,
 						},
-						"price": schema.Float64Attribute{
+						"price": schema.NumberAttribute{
 							Description: "Suggested cost of the coffee.",
+							CustomType:  moneyType{},
 							Computed:    true,
 						},
 						"image": schema.StringAttribute{
@@ -113,7 +113,7 @@ func (d *tryDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, re
 func (d *tryDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
 	var state tryDataSourceModel
 
-	// try, err := d.client.GetTry()
+	// try, err := d.client.GetTry(ctx)
 	// if err != nil {
 	// 	resp.Diagnostics.AddError(
 	// 		"Unable to Read fsd try",
@@ -129,7 +129,7 @@ func (d *tryDataSource) Read(ctx context.Context, req datasource.ReadRequest, re
 	// 		Name:        types.StringValue(coffee.Name),
 	// 		Teaser:      types.StringValue(coffee.Teaser),
 	// 		Description: types.StringValue(coffee.Description),
-	// 		Price:       types.Float64Value(coffee.Price),
+	// 		Price:       moneyFromFloat64(coffee.Price),
 	// 		Image:       types.StringValue(coffee.Image),
 	// 	}
 
@@ -158,5 +158,5 @@ func (d *tryDataSource) Configure(_ context.Context, req datasource.ConfigureReq
 		return
 	}
 
-	d.client = req.ProviderData.(*typs.Client)
+	d.client = req.ProviderData.(*fsdProviderData).client
 }
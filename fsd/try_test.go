@@ -38,7 +38,9 @@ resource "fsd_try" "test" {
 					resource.TestCheckResourceAttr("fsd_try.test", "items.0.coffee.teaser", "Automation in a cup"),
 					// Verify dynamic values have any value set in the state.
 					resource.TestCheckResourceAttrSet("fsd_try.test", "id"),
-					resource.TestCheckResourceAttrSet("fsd_try.test", "last_updated"),
+					resource.TestCheckResourceAttrSet("fsd_try.test", "created_at"),
+					resource.TestCheckResourceAttrSet("fsd_try.test", "updated_at"),
+					resource.TestCheckResourceAttrPair("fsd_try.test", "last_updated", "fsd_try.test", "updated_at"),
 				),
 			},
 			// ImportState testing
@@ -46,9 +48,6 @@ resource "fsd_try" "test" {
 				ResourceName:      "fsd_try.test",
 				ImportState:       true,
 				ImportStateVerify: true,
-				// The last_updated attribute does not exist in the fsd
-				// API, therefore there is no value for it during import.
-				ImportStateVerifyIgnore: []string{"last_updated"},
 			},
 			// Update and Read testing
 			{
@@ -0,0 +1,270 @@
+package fsd
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	typs "github.com/gofsd/fsd-types"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ resource.Resource              = &userResource{}
+	_ resource.ResourceWithConfigure = &userResource{}
+)
+
+// userResourceModel maps the resource schema data.
+type userResourceModel struct {
+	ID       types.String `tfsdk:"id"`
+	Username types.String `tfsdk:"username"`
+	Password types.String `tfsdk:"password"`
+}
+
+// NewUserResource is a helper function to simplify the provider implementation.
+func NewUserResource() resource.Resource {
+	return &userResource{}
+}
+
+// userResource is the resource implementation.
+type userResource struct {
+	client *fsdClient
+}
+
+// Configure adds the provider configured client to the resource.
+func (r *userResource) Configure(_ context.Context, req resource.ConfigureRequest, _ *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	r.client = req.ProviderData.(*fsdProviderData).client
+}
+
+// Metadata returns the resource type name.
+func (r *userResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_user"
+}
+
+// Schema defines the schema for the resource.
+func (r *userResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Manages a user account. The fsd API cannot remove accounts, so destroying " +
+			"this resource only removes it from Terraform state.\n\n" +
+			"The provider keeps no API token for the user: a token is issued whenever the account is " +
+			"checked or its password rotated, and revoked right after. The password is marked sensitive " +
+			"but still stored in the Terraform state, which refreshing and rotating it rely on. Keeping it " +
+			"out of state requires a write-only attribute, which needs terraform-plugin-framework v1.14 " +
+			"and Terraform 1.11 or later; until the provider moves to them, protect the state, for example " +
+			"with an encrypted remote backend.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Description: "Numeric identifier of the user.",
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"username": schema.StringAttribute{
+				Description: "Username of the account. Changing this creates a new account.",
+				Required:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"password": schema.StringAttribute{
+				Description: "Password of the account. Changing this rotates the password in place. " +
+					"Stored in the Terraform state, see the resource description.",
+				Required:  true,
+				Sensitive: true,
+			},
+		},
+	}
+}
+
+// Create a new resource
+func (r *userResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	// Retrieve values from plan
+	var plan userResourceModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Create new user
+	user, err := r.client.SignUp(ctx, typs.AuthStruct{
+		Username: plan.Username.ValueString(),
+		Password: plan.Password.ValueString(),
+	})
+	if err != nil {
+		addUserAPIErrorDiagnostics(
+			&resp.Diagnostics,
+			"Error creating user",
+			"Could not create user "+plan.Username.ValueString(),
+			plan.Username.ValueString(),
+			err,
+		)
+		return
+	}
+	r.revokeToken(ctx, plan.Username.ValueString(), user.Token)
+
+	// Map response body to schema and populate Computed attribute values
+	plan.ID = types.StringValue(strconv.Itoa(user.UserID))
+
+	// Set state to fully populated data
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// Read resource information
+func (r *userResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	// Get current state
+	var state userResourceModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// The fsd API has no user lookup, so confirm the account still accepts
+	// the stored password instead.
+	user, err := r.client.SignIn(ctx, typs.AuthStruct{
+		Username: state.Username.ValueString(),
+		Password: state.Password.ValueString(),
+	})
+	if isAPIStatus(err, http.StatusUnauthorized) {
+		// The password was changed outside of Terraform. Clearing it plans
+		// an update, which adopts the configured password if it matches.
+		resp.Diagnostics.AddAttributeWarning(
+			path.Root("password"),
+			"fsd User Password Changed",
+			"The fsd API rejected the password in state for user "+state.Username.ValueString()+
+				", so it was probably changed outside of Terraform. Set password to the current password "+
+				"of the account and apply to bring it back under management.",
+		)
+		state.Password = types.StringNull()
+	} else if err != nil {
+		addUserAPIErrorDiagnostics(
+			&resp.Diagnostics,
+			"Error Reading fsd User",
+			"Could not sign in as fsd user "+state.Username.ValueString(),
+			state.Username.ValueString(),
+			err,
+		)
+		return
+	} else {
+		r.revokeToken(ctx, state.Username.ValueString(), user.Token)
+		state.ID = types.StringValue(strconv.Itoa(user.UserID))
+	}
+
+	// Set refreshed state
+	diags = resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+func (r *userResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	// Retrieve values from plan and state
+	var plan, state userResourceModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	diags = req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	auth := typs.AuthStruct{
+		Username: plan.Username.ValueString(),
+		Password: plan.Password.ValueString(),
+	}
+
+	// Read clears the password when the API rejects it, and it may have
+	// been changed since the last refresh. The account can then only be
+	// brought under management if it already uses the configured password,
+	// which the sign-in below verifies.
+	if !state.Password.IsNull() {
+		current, err := r.client.SignIn(ctx, typs.AuthStruct{
+			Username: state.Username.ValueString(),
+			Password: state.Password.ValueString(),
+		})
+		switch {
+		case isAPIStatus(err, http.StatusUnauthorized):
+		case err != nil:
+			addUserAPIErrorDiagnostics(
+				&resp.Diagnostics,
+				"Error Updating fsd User",
+				"Could not sign in as fsd user "+state.Username.ValueString()+" before password rotation",
+				state.Username.ValueString(),
+				err,
+			)
+			return
+		default:
+			// Rotate the password with a token issued for the old one
+			err = r.client.UpdateUser(ctx, state.ID.ValueString(), current.Token, auth)
+			r.revokeToken(ctx, state.Username.ValueString(), current.Token)
+			if err != nil {
+				addUserAPIErrorDiagnostics(
+					&resp.Diagnostics,
+					"Error Updating fsd User",
+					"Could not update password of user "+plan.Username.ValueString(),
+					plan.Username.ValueString(),
+					err,
+				)
+				return
+			}
+		}
+	}
+
+	// Check that the account accepts the new password
+	user, err := r.client.SignIn(ctx, auth)
+	if err != nil {
+		addUserAPIErrorDiagnostics(
+			&resp.Diagnostics,
+			"Error Updating fsd User",
+			"Could not sign in as fsd user "+plan.Username.ValueString()+" after password rotation",
+			plan.Username.ValueString(),
+			err,
+		)
+		return
+	}
+	r.revokeToken(ctx, plan.Username.ValueString(), user.Token)
+
+	plan.ID = types.StringValue(strconv.Itoa(user.UserID))
+
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// Delete removes the user from state. The account cannot be removed and the
+// provider keeps no token for it, so there is nothing to revoke.
+func (r *userResource) Delete(_ context.Context, _ resource.DeleteRequest, _ *resource.DeleteResponse) {
+}
+
+// revokeToken signs out a token issued for a single operation, so no token
+// of the user outlives it. Failures are only logged: the operation itself
+// succeeded, and a token the API rejects is already revoked.
+func (r *userResource) revokeToken(ctx context.Context, username string, token string) {
+	err := r.client.SignOut(ctx, token)
+	if err != nil && !isAPIStatus(err, http.StatusUnauthorized) {
+		tflog.Warn(ctx, "Unable to revoke fsd user token", map[string]any{
+			"fsd_user": username,
+			"error":    err.Error(),
+		})
+	}
+}
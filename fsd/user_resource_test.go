@@ -0,0 +1,174 @@
+package fsd
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+
+	typs "github.com/gofsd/fsd-types"
+	fwresource "github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/hashicorp/terraform-plugin-testing/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccUserResource(t *testing.T) {
+	username := acctest.RandomWithPrefix("tf-acc")
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Create and Read testing
+			{
+				Config: providerConfig + testAccUserResourceConfig(username, "first-password"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("fsd_user.test", "username", username),
+					resource.TestCheckResourceAttr("fsd_user.test", "password", "first-password"),
+					// Verify dynamic values have any value set in the state.
+					resource.TestCheckResourceAttrSet("fsd_user.test", "id"),
+					// No token of the user is kept.
+					resource.TestCheckNoResourceAttr("fsd_user.test", "token"),
+				),
+			},
+			// Password rotation testing
+			{
+				Config: providerConfig + testAccUserResourceConfig(username, "second-password"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("fsd_user.test", "password", "second-password"),
+				),
+			},
+			// Password changed outside of Terraform
+			{
+				PreConfig: func() {
+					testAccRotateUserPassword(t, username, "second-password", "third-password")
+				},
+				Config: providerConfig + testAccUserResourceConfig(username, "third-password"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("fsd_user.test", "password", "third-password"),
+				),
+			},
+			// Aliased provider configured with the managed user
+			{
+				Config: providerConfig + testAccUserResourceConfig(username, "third-password") + `
+provider "fsd" {
+  alias    = "bootstrapped"
+  username = fsd_user.test.username
+  password = fsd_user.test.password
+  host     = "http://localhost:19090"
+}
+
+data "fsd_coffees" "test" {
+  provider = fsd.bootstrapped
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.fsd_coffees.test", "coffees.#", "9"),
+				),
+			},
+			// Delete testing automatically occurs in TestCase
+		},
+	})
+}
+
+func testAccUserResourceConfig(username, password string) string {
+	return fmt.Sprintf(`
+resource "fsd_user" "test" {
+  username = %[1]q
+  password = %[2]q
+}
+`, username, password)
+}
+
+// testAccRotateUserPassword changes the password of a user behind the back
+// of Terraform.
+func testAccRotateUserPassword(t *testing.T, username, oldPassword, newPassword string) {
+	t.Helper()
+
+	client := newTestClient("http://localhost:19090")
+	ctx := context.Background()
+
+	user, err := client.SignIn(ctx, typs.AuthStruct{Username: username, Password: oldPassword})
+	if err != nil {
+		t.Fatalf("signing in as %s: %s", username, err)
+	}
+
+	err = client.UpdateUser(ctx, strconv.Itoa(user.UserID), user.Token, typs.AuthStruct{Username: username, Password: newPassword})
+	if err != nil {
+		t.Fatalf("changing password of %s: %s", username, err)
+	}
+
+	client.SignOut(ctx, user.Token)
+}
+
+func TestUserResourceRevokesTokens(t *testing.T) {
+	var mu sync.Mutex
+	issued := map[string]bool{}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		switch r.URL.Path {
+		case "/signup", "/signin":
+			token := fmt.Sprintf("token-%d", len(issued)+1)
+			issued[token] = true
+			fmt.Fprintf(w, `{"user_id":7,"username":"alice","token":%q}`, token)
+		case "/signout":
+			if !issued[r.Header.Get("Authorization")] {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			issued[r.Header.Get("Authorization")] = false
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	ctx := context.Background()
+	r := &userResource{client: newTestClient(server.URL)}
+
+	var schemaResp fwresource.SchemaResponse
+	r.Schema(ctx, fwresource.SchemaRequest{}, &schemaResp)
+	objectType := schemaResp.Schema.Type().TerraformType(ctx).(tftypes.Object)
+
+	createReq := fwresource.CreateRequest{
+		Plan: tfsdk.Plan{
+			Schema: schemaResp.Schema,
+			Raw: tftypes.NewValue(objectType, map[string]tftypes.Value{
+				"id":       tftypes.NewValue(tftypes.String, tftypes.UnknownValue),
+				"username": tftypes.NewValue(tftypes.String, "alice"),
+				"password": tftypes.NewValue(tftypes.String, "first-password"),
+			}),
+		},
+	}
+	createResp := fwresource.CreateResponse{
+		State: tfsdk.State{Schema: schemaResp.Schema, Raw: tftypes.NewValue(objectType, nil)},
+	}
+	r.Create(ctx, createReq, &createResp)
+	if createResp.Diagnostics.HasError() {
+		t.Fatalf("unexpected error creating user: %v", createResp.Diagnostics)
+	}
+
+	readResp := fwresource.ReadResponse{State: createResp.State}
+	r.Read(ctx, fwresource.ReadRequest{State: createResp.State}, &readResp)
+	if readResp.Diagnostics.HasError() {
+		t.Fatalf("unexpected error reading user: %v", readResp.Diagnostics)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(issued) != 2 {
+		t.Errorf("expected a token to be issued by sign-up and by the refresh, got %d", len(issued))
+	}
+	for token, valid := range issued {
+		if valid {
+			t.Errorf("expected token %s to be revoked", token)
+		}
+	}
+}
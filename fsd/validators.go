@@ -0,0 +1,135 @@
+package fsd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var _ validator.String = stringOneOfValidator{}
+
+// stringOneOfValidator checks that a string attribute is one of a fixed set
+// of values.
+type stringOneOfValidator struct {
+	values []string
+}
+
+// stringOneOf returns a validator that accepts only the given values.
+func stringOneOf(values ...string) validator.String {
+	return stringOneOfValidator{values: values}
+}
+
+// Description describes the validation in plain text formatting.
+func (v stringOneOfValidator) Description(_ context.Context) string {
+	return fmt.Sprintf(`value must be one of: "%s"`, strings.Join(v.values, `", "`))
+}
+
+// MarkdownDescription describes the validation in Markdown formatting.
+func (v stringOneOfValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+// ValidateString performs the validation.
+func (v stringOneOfValidator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	value := req.ConfigValue.ValueString()
+	for _, allowed := range v.values {
+		if value == allowed {
+			return
+		}
+	}
+
+	resp.Diagnostics.AddAttributeError(
+		req.Path,
+		"Invalid Attribute Value",
+		fmt.Sprintf("Attribute %s %s, got: %q", req.Path, v.Description(ctx), value),
+	)
+}
+
+// Ensure the implementation satisfies the expected interfaces.
+var _ validator.Int64 = int64AtLeastValidator{}
+
+// int64AtLeastValidator checks that an integer attribute is not below a
+// minimum.
+type int64AtLeastValidator struct {
+	min int64
+}
+
+// int64AtLeast returns a validator that accepts only values of at least min.
+func int64AtLeast(min int64) validator.Int64 {
+	return int64AtLeastValidator{min: min}
+}
+
+// Description describes the validation in plain text formatting.
+func (v int64AtLeastValidator) Description(_ context.Context) string {
+	return fmt.Sprintf("value must be at least %d", v.min)
+}
+
+// MarkdownDescription describes the validation in Markdown formatting.
+func (v int64AtLeastValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+// ValidateInt64 performs the validation.
+func (v int64AtLeastValidator) ValidateInt64(ctx context.Context, req validator.Int64Request, resp *validator.Int64Response) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	value := req.ConfigValue.ValueInt64()
+	if value >= v.min {
+		return
+	}
+
+	resp.Diagnostics.AddAttributeError(
+		req.Path,
+		"Invalid Attribute Value",
+		fmt.Sprintf("Attribute %s %s, got: %d", req.Path, v.Description(ctx), value),
+	)
+}
+
+// Ensure the implementation satisfies the expected interfaces.
+var _ validator.String = apiPathValidator{}
+
+// apiPathValidator checks that a string attribute is a path of the fsd API
+// such as "/widgets".
+type apiPathValidator struct{}
+
+// apiPath returns a validator that accepts only paths starting with "/".
+func apiPath() validator.String {
+	return apiPathValidator{}
+}
+
+// Description describes the validation in plain text formatting.
+func (v apiPathValidator) Description(_ context.Context) string {
+	return `value must be a path of the fsd API starting with "/", such as "/widgets"`
+}
+
+// MarkdownDescription describes the validation in Markdown formatting.
+func (v apiPathValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+// ValidateString performs the validation.
+func (v apiPathValidator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	value := req.ConfigValue.ValueString()
+	if strings.HasPrefix(value, "/") {
+		return
+	}
+
+	resp.Diagnostics.AddAttributeError(
+		req.Path,
+		"Invalid Attribute Value",
+		fmt.Sprintf("Attribute %s %s, got: %q", req.Path, v.Description(ctx), value),
+	)
+}
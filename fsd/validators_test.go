@@ -0,0 +1,74 @@
+package fsd
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestStringOneOf(t *testing.T) {
+	v := stringOneOf(orderOnDestroyDelete, orderOnDestroyCancel, orderOnDestroyAbandon)
+
+	for value, expectError := range map[types.String]bool{
+		types.StringValue("cancel"):  false,
+		types.StringValue("archive"): true,
+		types.StringNull():           false,
+		types.StringUnknown():        false,
+	} {
+		resp := &validator.StringResponse{}
+		v.ValidateString(context.Background(), validator.StringRequest{
+			Path:        path.Root("on_destroy"),
+			ConfigValue: value,
+		}, resp)
+
+		if resp.Diagnostics.HasError() != expectError {
+			t.Errorf("%s: expected error %t, got %v", value, expectError, resp.Diagnostics)
+		}
+	}
+}
+
+func TestInt64AtLeast(t *testing.T) {
+	v := int64AtLeast(1)
+
+	for value, expectError := range map[types.Int64]bool{
+		types.Int64Value(1):  false,
+		types.Int64Value(0):  true,
+		types.Int64Value(-2): true,
+		types.Int64Null():    false,
+		types.Int64Unknown(): false,
+	} {
+		resp := &validator.Int64Response{}
+		v.ValidateInt64(context.Background(), validator.Int64Request{
+			Path:        path.Root("quantity"),
+			ConfigValue: value,
+		}, resp)
+
+		if resp.Diagnostics.HasError() != expectError {
+			t.Errorf("%s: expected error %t, got %v", value, expectError, resp.Diagnostics)
+		}
+	}
+}
+
+func TestAPIPath(t *testing.T) {
+	v := apiPath()
+
+	for value, expectError := range map[types.String]bool{
+		types.StringValue("/widgets"):             false,
+		types.StringValue("widgets"):              true,
+		types.StringValue("https://example.com/"): true,
+		types.StringNull():                        false,
+	} {
+		resp := &validator.StringResponse{}
+		v.ValidateString(context.Background(), validator.StringRequest{
+			Path:        path.Root("path"),
+			ConfigValue: value,
+		}, resp)
+
+		if resp.Diagnostics.HasError() != expectError {
+			t.Errorf("%s: expected error %t, got %v", value, expectError, resp.Diagnostics)
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package fsd
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+const (
+	// orderWaitDefaultTimeout is how long fsd_order waits for its
+	// wait_for_status unless the timeouts block sets the create or update
+	// timeout.
+	orderWaitDefaultTimeout = 20 * time.Minute
+
+	// orderWaitMinInterval is the delay before the first status poll.
+	orderWaitMinInterval = time.Second
+
+	// orderWaitMaxInterval caps the delay between status polls.
+	orderWaitMaxInterval = 30 * time.Second
+)
+
+// orderFinalStatuses are statuses an order never leaves, so waiting for any
+// other status ends as soon as one of them is reached.
+var orderFinalStatuses = map[string]bool{
+	"fulfilled": true,
+	"cancelled": true,
+	"failed":    true,
+}
+
+// orderStatusWaiter polls an order with exponential backoff until it
+// reaches a status.
+type orderStatusWaiter struct {
+	client      *fsdClient
+	minInterval time.Duration
+	maxInterval time.Duration
+}
+
+// newOrderStatusWaiter returns a waiter that polls with client.
+func newOrderStatusWaiter(client *fsdClient) *orderStatusWaiter {
+	return &orderStatusWaiter{
+		client:      client,
+		minInterval: orderWaitMinInterval,
+		maxInterval: orderWaitMaxInterval,
+	}
+}
+
+// wait polls the order until its status is status and returns the order and
+// ETag as last read. It fails if the order ends in another final status or
+// the timeout expires first.
+func (w *orderStatusWaiter) wait(ctx context.Context, orderID string, status string, timeout time.Duration) (*apiOrder, string, error) {
+	deadline := time.Now().Add(timeout)
+	interval := w.minInterval
+
+	for {
+		order, etag, err := w.client.GetOrder(ctx, orderID)
+		if err != nil {
+			return nil, "", err
+		}
+
+		tflog.Debug(ctx, "Polled fsd order status", map[string]interface{}{
+			"order_id":        orderID,
+			"status":          order.Status,
+			"wait_for_status": status,
+		})
+
+		if order.Status == status {
+			return order, etag, nil
+		}
+		if orderFinalStatuses[order.Status] {
+			return order, etag, fmt.Errorf("order %s reached final status %q while waiting for status %q", orderID, order.Status, status)
+		}
+
+		remaining := time.Until(deadline)
+		if remaining <= 0 {
+			return order, etag, fmt.Errorf("timed out after %s waiting for order %s to reach status %q, last status %q", timeout, orderID, status, order.Status)
+		}
+
+		timer := time.NewTimer(min(interval, remaining))
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, "", ctx.Err()
+		}
+
+		interval = min(2*interval, w.maxInterval)
+	}
+}
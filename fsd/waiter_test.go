@@ -0,0 +1,125 @@
+package fsd
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// newAdvancingOrderServer returns a local fsd API whose order moves to the
+// next status every time it is read.
+func newAdvancingOrderServer(statuses ...string) (*httptest.Server, *atomic.Int32) {
+	var reads atomic.Int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		i := int(reads.Add(1)) - 1
+		status := statuses[min(i, len(statuses)-1)]
+
+		w.Header().Set("ETag", fmt.Sprintf(`"v%d"`, i))
+		fmt.Fprintf(w, `{"id":1,"items":[],"status":%q}`, status)
+	}))
+
+	return server, &reads
+}
+
+func newTestOrderStatusWaiter(url string) *orderStatusWaiter {
+	waiter := newOrderStatusWaiter(newTestClient(url))
+	waiter.minInterval = time.Millisecond
+	waiter.maxInterval = 4 * time.Millisecond
+
+	return waiter
+}
+
+func TestOrderStatusWaiterReachesStatus(t *testing.T) {
+	server, reads := newAdvancingOrderServer("pending", "preparing", "preparing", "fulfilled")
+	defer server.Close()
+
+	order, etag, err := newTestOrderStatusWaiter(server.URL).wait(context.Background(), "1", "fulfilled", time.Minute)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if order.Status != "fulfilled" {
+		t.Errorf("expected fulfilled order, got %q", order.Status)
+	}
+	if etag != `"v3"` {
+		t.Errorf("expected ETag of the last read, got %s", etag)
+	}
+	if got := reads.Load(); got != 4 {
+		t.Errorf("expected polling to stop once the status is reached, got %d reads", got)
+	}
+}
+
+func TestOrderStatusWaiterStopsAtOtherFinalStatus(t *testing.T) {
+	server, _ := newAdvancingOrderServer("pending", "cancelled")
+	defer server.Close()
+
+	order, _, err := newTestOrderStatusWaiter(server.URL).wait(context.Background(), "1", "fulfilled", time.Minute)
+	if err == nil || !strings.Contains(err.Error(), `final status "cancelled"`) {
+		t.Fatalf("expected final status error, got: %v", err)
+	}
+	if order == nil || order.Status != "cancelled" {
+		t.Errorf("expected last read order to be returned, got %v", order)
+	}
+}
+
+func TestOrderStatusWaiterTimesOut(t *testing.T) {
+	server, _ := newAdvancingOrderServer("pending")
+	defer server.Close()
+
+	start := time.Now()
+	_, _, err := newTestOrderStatusWaiter(server.URL).wait(context.Background(), "1", "fulfilled", 50*time.Millisecond)
+	if err == nil || !strings.Contains(err.Error(), `last status "pending"`) {
+		t.Fatalf("expected timeout error, got: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("expected waiting to stop at the timeout, took %s", elapsed)
+	}
+}
+
+func TestOrderStatusWaiterHonorsContext(t *testing.T) {
+	server, _ := newAdvancingOrderServer("pending")
+	defer server.Close()
+
+	waiter := newTestOrderStatusWaiter(server.URL)
+	waiter.minInterval = time.Hour
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	if _, _, err := waiter.wait(ctx, "1", "fulfilled", time.Hour); err != context.DeadlineExceeded {
+		t.Errorf("expected waiting to stop with the context, got: %v", err)
+	}
+}
+
+func TestOrderWaitTimeout(t *testing.T) {
+	ctx := context.Background()
+	timeoutTypes := map[string]attr.Type{"create": types.StringType, "update": types.StringType}
+
+	plan := orderResourceModel{
+		Timeouts: timeouts.Value{Object: types.ObjectNull(timeoutTypes)},
+	}
+	if got, _ := plan.Timeouts.Create(ctx, orderWaitDefaultTimeout); got != 20*time.Minute {
+		t.Errorf("expected the default without a timeouts block, got %s", got)
+	}
+
+	plan.Timeouts = timeouts.Value{Object: types.ObjectValueMust(timeoutTypes, map[string]attr.Value{
+		"create": types.StringValue("90s"),
+		"update": types.StringNull(),
+	})}
+	if got, _ := plan.Timeouts.Create(ctx, orderWaitDefaultTimeout); got != 90*time.Second {
+		t.Errorf("expected the create timeout to override the default, got %s", got)
+	}
+	if got, _ := plan.Timeouts.Update(ctx, orderWaitDefaultTimeout); got != 20*time.Minute {
+		t.Errorf("expected the default without an update timeout, got %s", got)
+	}
+}